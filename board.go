@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	boardGap            = "  "
+	boardMinColumnWidth = 20
+)
+
+var boardGroups = []string{"status", "priority", "assignee", "area"}
+
+// BoardOptions are the 'task board' settings beyond the task filters
+type BoardOptions struct {
+	Group string
+	Width int // 0 means the terminal's width
+}
+
+type boardColumn struct {
+	name  string
+	tasks []TaskInfo
+}
+
+// taskBoard shows tasks as kanban columns. Without -all it leaves out
+// dropped tasks and tasks done before this week.
+func taskBoard(config Config, filters TaskFilters, opts BoardOptions) error {
+	if !containsTag(boardGroups, opts.Group) {
+		return fmt.Errorf("invalid -group %q (use %s)", opts.Group, strings.Join(boardGroups, ", "))
+	}
+
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+
+	weekStart := startOfWeek(time.Now()).Format("2006-01-02")
+	var shown []TaskInfo
+	for _, task := range tasks {
+		if !filters.All {
+			// Like 'report weekly', a done task counts as done on its
+			// completed_date (or, without one, when its file last changed)
+			if task.Status == "dropped" && filters.Status != "dropped" {
+				continue
+			}
+			if task.Status == "done" && task.completedOn() < weekStart {
+				continue
+			}
+		}
+		shown = append(shown, task)
+	}
+	if len(shown) == 0 {
+		fmt.Println("No tasks found")
+		return nil
+	}
+
+	width := opts.Width
+	if width <= 0 {
+		width = boardWidth()
+	}
+
+	fmt.Printf("%s %s:\n\n", bold("Tasks by"), opts.Group)
+	printBoard(boardColumns(shown, opts.Group, filters), opts.Group, width)
+
+	saveTaskIndexCache(config, shown)
+	return nil
+}
+
+// boardColumns sorts tasks into columns. Grouped by status, the columns are
+// fixed so the board keeps its shape; other groupings get a column for each
+// value in use.
+func boardColumns(tasks []TaskInfo, group string, filters TaskFilters) []*boardColumn {
+	if group == "status" {
+		doneName := "Done this week"
+		if filters.All {
+			doneName = "Done"
+		}
+		columns := []*boardColumn{{name: "Open"}, {name: "Paused"}, {name: "Delegated"}, {name: doneName}, {name: "Dropped"}}
+		statuses := []string{"open", "paused", "delegated", "done", "dropped"}
+		for _, task := range tasks {
+			for i, s := range statuses {
+				if task.Status == s {
+					columns[i].tasks = append(columns[i].tasks, task)
+				}
+			}
+		}
+
+		var result []*boardColumn
+		for i, column := range columns {
+			switch {
+			case filters.Status != "" && statuses[i] != filters.Status:
+			case statuses[i] == "dropped" && len(column.tasks) == 0:
+			default:
+				result = append(result, column)
+			}
+		}
+		return result
+	}
+
+	none := map[string]string{"priority": "No priority", "assignee": "Unassigned", "area": "No area"}[group]
+	byName := make(map[string]*boardColumn)
+	var names []string
+	for _, task := range tasks {
+		name := task.Area
+		switch group {
+		case "priority":
+			name = strings.ToUpper(task.Priority)
+		case "assignee":
+			name = task.Assignee
+		}
+		if name == "" {
+			name = none
+		}
+		column, ok := byName[name]
+		if !ok {
+			column = &boardColumn{name: name}
+			byName[name] = column
+			names = append(names, name)
+		}
+		column.tasks = append(column.tasks, task)
+	}
+
+	// P1, P2, P3 and other names sort alphabetically; the empty group goes last
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == none) != (names[j] == none) {
+			return names[j] == none
+		}
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	result := make([]*boardColumn, len(names))
+	for i, name := range names {
+		result[i] = byName[name]
+	}
+	return result
+}
+
+// printBoard lays columns out side by side, wrapping them into bands when
+// the terminal is too narrow for all of them
+func printBoard(columns []*boardColumn, group string, width int) {
+	perBand := (width + len(boardGap)) / (boardMinColumnWidth + len(boardGap))
+	perBand = max(1, min(perBand, len(columns)))
+	columnWidth := (width - len(boardGap)*(perBand-1)) / perBand
+
+	for start := 0; start < len(columns); start += perBand {
+		band := columns[start:min(start+perBand, len(columns))]
+
+		var rendered [][]string
+		height := 0
+		for _, column := range band {
+			lines := boardColumnLines(column, group, columnWidth)
+			rendered = append(rendered, lines)
+			height = max(height, len(lines))
+		}
+
+		blank := strings.Repeat(" ", columnWidth)
+		for row := 0; row < height; row++ {
+			cells := make([]string, len(rendered))
+			for i, lines := range rendered {
+				cells[i] = blank
+				if row < len(lines) {
+					cells[i] = lines[row]
+				}
+			}
+			fmt.Println(strings.TrimRight(strings.Join(cells, boardGap), " "))
+		}
+		fmt.Println()
+	}
+}
+
+// boardColumnLines renders a column header and its cards, each line exactly
+// width columns wide
+func boardColumnLines(column *boardColumn, group string, width int) []string {
+	plain := !colorEnabled
+	lines := []string{
+		fitSegments([]segment{
+			{column.name, bold},
+			{" " + strconv.Itoa(len(column.tasks)), dim},
+		}, width, plain),
+		fitSegments([]segment{{strings.Repeat("─", width), dim}}, width, plain),
+	}
+
+	for _, task := range column.tasks {
+		id := task.Index
+		if task.TaskID > 0 {
+			id = task.TaskID
+		}
+
+		title := []segment{{fmt.Sprintf("%3d ", id), bold}}
+		if group != "status" {
+			title = append(title, segment{getStatusIcon(task.Status) + " ", statusStyle(task.Status)})
+		}
+		if task.Priority != "" && group != "priority" {
+			title = append(title, segment{"[" + strings.ToUpper(task.Priority) + "] ", priorityStyle(task.Priority)})
+		}
+		title = append(title, segment{task.Note.Title, nil})
+		lines = append(lines, fitSegments(title, width, plain))
+
+		details := []segment{{"   ", nil}}
+		if task.Project != "" {
+			details = append(details, segment{" @" + task.Project, blue})
+		}
+		if task.Area != "" && group != "area" {
+			details = append(details, segment{" #" + task.Area, magenta})
+		}
+		if task.Assignee != "" && group != "assignee" {
+			details = append(details, segment{" →" + task.Assignee, cyan})
+		}
+		if task.DueDate != "" && task.Status != "done" {
+			overdueFlag := isOverdue(task.DueDate)
+			details = append(details, segment{formatDueDate(task.DueDate), func(s string) string { return due(s, overdueFlag) }})
+		}
+		if len(details) > 1 {
+			lines = append(lines, fitSegments(details, width, plain))
+		}
+	}
+	return lines
+}
+
+// boardWidth is the terminal's width, or $COLUMNS, or 80
+func boardWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+// startOfWeek returns midnight on the Monday of t's week
+func startOfWeek(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, t.Location())
+}
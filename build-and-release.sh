@@ -131,3 +131,4 @@ echo ""
 echo "After installation, install completions with:"
 echo "  sudo cp completions/_notes-cli /usr/local/share/zsh/site-functions/"
 echo "  sudo cp completions/notes-cli.bash /usr/local/etc/bash_completion.d/"
+echo "  cp completions/notes-cli.fish ~/.config/fish/completions/"
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// errPreconditionFailed means the remote resource changed since its ETag was read
+var errPreconditionFailed = errors.New("changed on the server since the last sync")
+
+// caldavClient talks to a single CalDAV calendar collection. Only the small
+// subset of WebDAV needed for syncing VTODOs is used, so any server
+// (including a local stub) that answers calendar-query REPORTs and
+// conditional PUT/DELETE works.
+type caldavClient struct {
+	base     *url.URL
+	username string
+	password string
+	http     *http.Client
+}
+
+// caldavResource is one calendar object in the collection
+type caldavResource struct {
+	Href string
+	ETag string
+	Data string
+}
+
+func newCalDAVClient(cfg CalDAVConfig) (*caldavClient, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no CalDAV URL configured (set url in the [caldav] section of config.toml or use -url)")
+	}
+
+	base, err := url.Parse(cfg.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid CalDAV URL: %s", cfg.URL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	password := cfg.Password
+	if cfg.PasswordCommand != "" {
+		out, err := exec.Command("sh", "-c", cfg.PasswordCommand).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to run password_command: %w", err)
+		}
+		password = strings.TrimRight(string(out), "\r\n")
+	}
+	if env := os.Getenv("NOTES_CLI_CALDAV_PASSWORD"); env != "" {
+		password = env
+	}
+
+	return &caldavClient{
+		base:     base,
+		username: cfg.Username,
+		password: password,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// resolve turns an href from the server into an absolute URL
+func (c *caldavClient) resolve(href string) string {
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return c.base.ResolveReference(ref).String()
+}
+
+func (c *caldavClient) do(method, href string, body []byte, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.resolve(href), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, href, err)
+	}
+	return resp, nil
+}
+
+const caldavQuery = `<?xml version="1.0" encoding="utf-8" ?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data/>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO"/>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+
+type davMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag         string `xml:"DAV: getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// list returns every VTODO resource in the collection
+func (c *caldavClient) list() ([]caldavResource, error) {
+	resp, err := c.do("REPORT", c.base.String(), []byte(caldavQuery), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("failed to list tasks on the server: %s", resp.Status)
+	}
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("failed to parse server response: %w", err)
+	}
+
+	var resources []caldavResource
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if ps.Status != "" && !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			if ps.Prop.CalendarData == "" {
+				continue
+			}
+			resources = append(resources, caldavResource{
+				Href: c.resolve(r.Href),
+				ETag: ps.Prop.ETag,
+				Data: ps.Prop.CalendarData,
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// put stores a calendar object. A new object is only created if nothing
+// exists at href; an existing one is only replaced if it still has etag.
+// It returns the new ETag, which may be empty if the server doesn't send one.
+func (c *caldavClient) put(href, data, etag string, create bool) (string, error) {
+	headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	if create {
+		headers["If-None-Match"] = "*"
+	} else if etag != "" {
+		headers["If-Match"] = etag
+	}
+
+	resp, err := c.do(http.MethodPut, href, []byte(data), headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", errPreconditionFailed
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return "", fmt.Errorf("failed to upload %s: %s", href, resp.Status)
+	}
+
+	return resp.Header.Get("ETag"), nil
+}
+
+// delete removes a calendar object if it is unchanged
+func (c *caldavClient) delete(href, etag string) error {
+	headers := map[string]string{}
+	if etag != "" {
+		headers["If-Match"] = etag
+	}
+
+	resp, err := c.do(http.MethodDelete, href, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return errPreconditionFailed
+	case resp.StatusCode == http.StatusNotFound:
+		return nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("failed to delete %s: %s", href, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,618 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CalDAVSyncOptions struct {
+	URL      string
+	Username string
+	DryRun   bool
+	Prefer   string // local or remote; empty reports conflicts and skips them
+}
+
+// caldavFields are the task fields kept in sync. Values are normalized so
+// that anything which round-trips through iCalendar compares equal (a
+// priority of 2 on the server reads back as p1). Statuses iCalendar has no
+// name for are kept in icsStatusProperty.
+type caldavFields struct {
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Priority  string `json:"priority"`
+	DueDate   string `json:"due_date"`
+	StartDate string `json:"start_date"`
+}
+
+var caldavFieldNames = []string{"title", "status", "priority", "due_date", "start_date"}
+
+func (f *caldavFields) pointers() []*string {
+	return []*string{&f.Title, &f.Status, &f.Priority, &f.DueDate, &f.StartDate}
+}
+
+// CalDAVSyncItem links a task to its calendar object. Base holds the field
+// values at the last sync, so changes on each side can be told apart.
+type CalDAVSyncItem struct {
+	UID           string       `json:"uid"`
+	Href          string       `json:"href"`
+	ETag          string       `json:"etag"`
+	Base          caldavFields `json:"base"`
+	RemoteDeleted bool         `json:"remote_deleted,omitempty"`
+}
+
+// CalDAVState is persisted in the task directory between syncs
+type CalDAVState struct {
+	URL   string                     `json:"url"`
+	Items map[string]*CalDAVSyncItem `json:"items"` // by Denote ID
+}
+
+func caldavStatePath(config Config) string {
+	return filepath.Join(config.TaskDir, ".notes-cli-caldav-state.json")
+}
+
+func loadCalDAVState(config Config) (*CalDAVState, error) {
+	state := &CalDAVState{Items: make(map[string]*CalDAVSyncItem)}
+
+	data, err := os.ReadFile(caldavStatePath(config))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state %s: %w", caldavStatePath(config), err)
+	}
+	if state.Items == nil {
+		state.Items = make(map[string]*CalDAVSyncItem)
+	}
+
+	return state, nil
+}
+
+func (s *CalDAVState) save(config Config) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	if err := os.WriteFile(caldavStatePath(config), data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+
+	return nil
+}
+
+func taskStatusFromICS(status string) string {
+	switch strings.ToUpper(status) {
+	case "COMPLETED":
+		return "done"
+	case "CANCELLED":
+		return "dropped"
+	case "IN-PROCESS":
+		return "delegated"
+	}
+	return "open"
+}
+
+// priorityFromICS maps the iCalendar 1-9 scale onto p1-p3
+func priorityFromICS(value string) string {
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil || n <= 0:
+		return ""
+	case n < 5:
+		return "p1"
+	case n == 5:
+		return "p2"
+	}
+	return "p3"
+}
+
+func localSyncFields(task *TaskInfo) caldavFields {
+	status := task.Status
+	if !isValidStatus(status) {
+		status = taskStatusFromICS(icsTaskStatus(status))
+	}
+	return caldavFields{
+		Title:     task.Note.Title,
+		Status:    status,
+		Priority:  priorityFromICS(icsPriority(task.Priority)),
+		DueDate:   task.DueDate,
+		StartDate: task.StartDate,
+	}
+}
+
+func remoteSyncFields(todo icsComponent) caldavFields {
+	fields := caldavFields{
+		Title:    todo.text("SUMMARY"),
+		Status:   taskStatusFromICS(todo.text("STATUS")),
+		Priority: priorityFromICS(todo.text("PRIORITY")),
+	}
+	// A client that changed STATUS leaves a stale extension behind
+	if ext := todo.text(icsStatusProperty); isValidStatus(ext) && taskStatusFromICS(icsTaskStatus(ext)) == fields.Status {
+		fields.Status = ext
+	}
+	if due, ok := todo.get("DUE"); ok {
+		fields.DueDate = icsValueDate(due.Value)
+	}
+	if start, ok := todo.get("DTSTART"); ok {
+		fields.StartDate = icsValueDate(start.Value)
+	}
+	return fields
+}
+
+// changedFields lists the names of the fields that differ
+func changedFields(a, b caldavFields) []string {
+	var names []string
+	pa, pb := a.pointers(), b.pointers()
+	for i := range pa {
+		if *pa[i] != *pb[i] {
+			names = append(names, caldavFieldNames[i])
+		}
+	}
+	return names
+}
+
+// mergeSyncFields takes each field from whichever side changed it since the
+// last sync. Fields changed differently on both sides are conflicts unless
+// prefer picks a side.
+func mergeSyncFields(base, local, remote caldavFields, prefer string) (caldavFields, []string) {
+	merged := local
+	var conflicts []string
+
+	pb, pl, pr, pm := base.pointers(), local.pointers(), remote.pointers(), merged.pointers()
+	for i := range pb {
+		switch {
+		case *pl[i] == *pr[i]:
+		case *pl[i] == *pb[i]:
+			*pm[i] = *pr[i]
+		case *pr[i] == *pb[i]:
+		case prefer == "remote":
+			*pm[i] = *pr[i]
+		case prefer == "local":
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("%s: local %q, server %q", caldavFieldNames[i], *pl[i], *pr[i]))
+		}
+	}
+
+	return merged, conflicts
+}
+
+// patchVTODO rewrites only the changed properties of the VTODO in a
+// calendar object, keeping anything else other clients stored (alarms,
+// descriptions, subtasks)
+func patchVTODO(data string, remote, merged caldavFields) string {
+	now := icsTimestamp(time.Now())
+	drop := map[string]bool{"DTSTAMP": true, "LAST-MODIFIED": true}
+	add := &icsWriter{}
+
+	if merged.Title != remote.Title {
+		drop["SUMMARY"] = true
+		add.text("SUMMARY", merged.Title)
+	}
+	if merged.Status != remote.Status {
+		drop["STATUS"], drop["COMPLETED"], drop["PERCENT-COMPLETE"], drop[icsStatusProperty] = true, true, true, true
+		add.line("STATUS", icsTaskStatus(merged.Status))
+		if ext := icsStatusExtension(merged.Status); ext != "" {
+			add.line(icsStatusProperty, ext)
+		}
+		if merged.Status == "done" {
+			add.line("COMPLETED", now)
+			add.line("PERCENT-COMPLETE", "100")
+		}
+	}
+	if merged.Priority != remote.Priority {
+		drop["PRIORITY"] = true
+		if p := icsPriority(merged.Priority); p != "" {
+			add.line("PRIORITY", p)
+		}
+	}
+	if merged.DueDate != remote.DueDate {
+		drop["DUE"] = true
+		if d, ok := icsDate(merged.DueDate); ok {
+			add.line("DUE;VALUE=DATE", d)
+		}
+	}
+	if merged.StartDate != remote.StartDate {
+		drop["DTSTART"] = true
+		if d, ok := icsDate(merged.StartDate); ok {
+			add.line("DTSTART;VALUE=DATE", d)
+		}
+	}
+	add.line("DTSTAMP", now)
+	add.line("LAST-MODIFIED", now)
+
+	w := &icsWriter{}
+	inTodo := false
+	depth := 0
+	for _, line := range unfoldICS(data) {
+		prop := parseICSLine(line)
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VTODO") && !inTodo:
+			inTodo = true
+		case prop.Name == "BEGIN" && inTodo:
+			depth++
+		case prop.Name == "END" && inTodo && depth > 0:
+			depth--
+		case prop.Name == "END" && inTodo:
+			w.b.WriteString(add.b.String())
+			inTodo = false
+		case inTodo && depth == 0 && drop[prop.Name]:
+			continue
+		}
+		w.raw(line)
+	}
+
+	return w.b.String()
+}
+
+// newVTODO renders a task as a new calendar object
+func newVTODO(task *TaskInfo) string {
+	w := &icsWriter{}
+	w.beginCalendar()
+	w.todo(taskICSItem(task))
+	w.line("END", "VCALENDAR")
+	return w.b.String()
+}
+
+// remoteTodo is a calendar object from the server with its parsed VTODO
+type remoteTodo struct {
+	caldavResource
+	todo   icsComponent
+	fields caldavFields
+	seen   bool
+}
+
+type caldavSync struct {
+	config     Config
+	client     *caldavClient
+	state      *CalDAVState
+	opts       CalDAVSyncOptions
+	remote     map[string]*remoteTodo // by href
+	uploaded   int
+	downloaded int
+	created    int
+	deleted    int
+	conflicts  int
+}
+
+func syncCalDAV(config Config, opts CalDAVSyncOptions) error {
+	if opts.Prefer != "" && opts.Prefer != "local" && opts.Prefer != "remote" {
+		return fmt.Errorf("invalid -prefer: %s (must be local or remote)", opts.Prefer)
+	}
+
+	cfg := config.TOMLConfig.CalDAV
+	if opts.URL != "" {
+		cfg.URL = opts.URL
+	}
+	if opts.Username != "" {
+		cfg.Username = opts.Username
+	}
+
+	client, err := newCalDAVClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	state, err := loadCalDAVState(config)
+	if err != nil {
+		return err
+	}
+	if state.URL != "" && state.URL != client.base.String() {
+		return fmt.Errorf("sync state belongs to %s; remove %s to sync with a different calendar", state.URL, caldavStatePath(config))
+	}
+	state.URL = client.base.String()
+
+	resources, err := client.list()
+	if err != nil {
+		return err
+	}
+
+	s := &caldavSync{
+		config: config,
+		client: client,
+		state:  state,
+		opts:   opts,
+		remote: make(map[string]*remoteTodo),
+	}
+
+	byUID := make(map[string]*remoteTodo)
+	for _, res := range resources {
+		todos := parseICSComponents(res.Data, "VTODO")
+		if len(todos) == 0 {
+			continue
+		}
+		rt := &remoteTodo{caldavResource: res, todo: todos[0], fields: remoteSyncFields(todos[0])}
+		s.remote[res.Href] = rt
+		byUID[todos[0].text("UID")] = rt
+	}
+
+	tasks, err := findTasks(config, TaskFilters{All: true})
+	if err != nil {
+		return err
+	}
+	localByID := make(map[string]*TaskInfo)
+	for i := range tasks {
+		localByID[tasks[i].Note.ID] = &tasks[i]
+	}
+
+	if opts.DryRun {
+		fmt.Println(dim("Dry run: nothing will be changed"))
+	}
+
+	// Tasks synced before
+	var ids []string
+	for id := range state.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := s.syncItem(id, state.Items[id], localByID[id]); err != nil {
+			return err
+		}
+	}
+
+	// New local tasks
+	for i := range tasks {
+		task := &tasks[i]
+		if _, ok := state.Items[task.Note.ID]; ok {
+			continue
+		}
+
+		uid := task.Note.ID + "@notes-cli"
+		if rt, ok := byUID[uid]; ok && !rt.seen {
+			// Uploaded before but the sync state was lost: start from the
+			// server's copy, so local differences are pushed
+			item := &CalDAVSyncItem{UID: uid, Href: rt.Href, ETag: rt.ETag, Base: rt.fields}
+			state.Items[task.Note.ID] = item
+			if err := s.syncItem(task.Note.ID, item, task); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Don't upload history
+		if task.Status == "done" || task.Status == "dropped" {
+			continue
+		}
+		if err := s.upload(task); err != nil {
+			return err
+		}
+	}
+
+	// New tasks on the server
+	var hrefs []string
+	for href, rt := range s.remote {
+		if !rt.seen {
+			hrefs = append(hrefs, href)
+		}
+	}
+	sort.Strings(hrefs)
+	for _, href := range hrefs {
+		if err := s.download(s.remote[href]); err != nil {
+			return err
+		}
+	}
+
+	if !opts.DryRun {
+		if err := state.save(config); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s Synced with %s: %d uploaded, %d downloaded, %d created, %d deleted",
+		success("✓"), bold(client.base.Host), s.uploaded, s.downloaded, s.created, s.deleted)
+	if s.conflicts > 0 {
+		fmt.Printf(", %s\n", warning(fmt.Sprintf("%d %s", s.conflicts, pluralize(s.conflicts, "conflict", "conflicts"))))
+		fmt.Println("→ Resolve by editing either side, or run again with -prefer local or -prefer remote")
+	} else {
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func taskLabel(task *TaskInfo, title string) string {
+	if task != nil && task.TaskID > 0 {
+		return fmt.Sprintf("#%d %s", task.TaskID, title)
+	}
+	return title
+}
+
+func (s *caldavSync) conflict(label string, details ...string) {
+	s.conflicts++
+	fmt.Printf("  %s %s\n", warning("!"), label)
+	for _, detail := range details {
+		fmt.Printf("      %s\n", detail)
+	}
+}
+
+// syncItem reconciles a task that was synced before with the server
+func (s *caldavSync) syncItem(id string, item *CalDAVSyncItem, task *TaskInfo) error {
+	rt := s.remote[item.Href]
+	if rt != nil {
+		rt.seen = true
+	}
+
+	switch {
+	case task == nil && rt == nil:
+		delete(s.state.Items, id)
+		return nil
+
+	case task == nil:
+		if len(changedFields(item.Base, rt.fields)) > 0 {
+			// Keep the server's edits by bringing the task back
+			s.conflict(item.Base.Title + ": deleted locally but changed on the server; restoring")
+			delete(s.state.Items, id)
+			rt.seen = false
+			return nil
+		}
+		fmt.Printf("  %s %s %s\n", red("✗"), item.Base.Title, dim("(deleted locally)"))
+		if !s.opts.DryRun {
+			if err := s.client.delete(rt.Href, rt.ETag); err != nil {
+				if errors.Is(err, errPreconditionFailed) {
+					s.conflict(item.Base.Title + ": " + err.Error())
+					return nil
+				}
+				return err
+			}
+			delete(s.state.Items, id)
+		}
+		s.deleted++
+		return nil
+
+	case rt == nil:
+		if item.RemoteDeleted {
+			return nil
+		}
+		local := localSyncFields(task)
+		if len(changedFields(item.Base, local)) == 0 {
+			fmt.Printf("  %s %s %s\n", red("✗"), taskLabel(task, local.Title), dim("(deleted on the server; kept locally and no longer synced)"))
+			item.RemoteDeleted = true
+			return nil
+		}
+		// Changed locally since, so upload it again
+		delete(s.state.Items, id)
+		return s.upload(task)
+	}
+
+	local := localSyncFields(task)
+	merged, conflicts := mergeSyncFields(item.Base, local, rt.fields, s.opts.Prefer)
+	if len(conflicts) > 0 {
+		s.conflict(taskLabel(task, local.Title)+": changed on both sides", conflicts...)
+		return nil
+	}
+
+	if changed := changedFields(local, merged); len(changed) > 0 {
+		fmt.Printf("  %s %s %s\n", info("↓"), taskLabel(task, merged.Title), dim("("+strings.Join(changed, ", ")+")"))
+		if !s.opts.DryRun {
+			if _, err := rewriteTask(s.config, task.Path, func(fm *TaskFrontmatter) {
+				if merged.Title != local.Title {
+					fm.Title = merged.Title
+				}
+				if merged.Status != local.Status {
+					fm.Status = merged.Status
+				}
+				if merged.Priority != local.Priority {
+					fm.Priority = merged.Priority
+				}
+				if merged.DueDate != local.DueDate {
+					fm.DueDate = merged.DueDate
+				}
+				if merged.StartDate != local.StartDate {
+					fm.StartDate = merged.StartDate
+				}
+			}); err != nil {
+				return err
+			}
+		}
+		s.downloaded++
+	}
+
+	item.ETag = rt.ETag
+	if changed := changedFields(rt.fields, merged); len(changed) > 0 {
+		fmt.Printf("  %s %s %s\n", success("↑"), taskLabel(task, merged.Title), dim("("+strings.Join(changed, ", ")+")"))
+		if !s.opts.DryRun {
+			etag, err := s.client.put(rt.Href, patchVTODO(rt.Data, rt.fields, merged), rt.ETag, false)
+			if err != nil {
+				if errors.Is(err, errPreconditionFailed) {
+					s.conflict(taskLabel(task, merged.Title) + ": " + err.Error())
+					return nil
+				}
+				return err
+			}
+			item.ETag = etag
+		}
+		s.uploaded++
+	}
+
+	item.Base = merged
+	return nil
+}
+
+// upload creates a calendar object for a task that hasn't been synced
+func (s *caldavSync) upload(task *TaskInfo) error {
+	uid := task.Note.ID + "@notes-cli"
+	href := s.client.resolve(task.Note.ID + ".ics")
+
+	fmt.Printf("  %s %s %s\n", success("↑"), taskLabel(task, task.Note.Title), dim("(new)"))
+	s.uploaded++
+	if s.opts.DryRun {
+		return nil
+	}
+
+	etag, err := s.client.put(href, newVTODO(task), "", true)
+	if err != nil {
+		if errors.Is(err, errPreconditionFailed) {
+			s.conflict(taskLabel(task, task.Note.Title) + ": a different calendar object already exists at " + href)
+			return nil
+		}
+		return err
+	}
+
+	s.state.Items[task.Note.ID] = &CalDAVSyncItem{
+		UID:  uid,
+		Href: href,
+		ETag: etag,
+		Base: localSyncFields(task),
+	}
+	return nil
+}
+
+// download creates a task for a calendar object that isn't linked to one
+func (s *caldavSync) download(rt *remoteTodo) error {
+	fields := rt.fields
+	if fields.Title == "" {
+		return nil
+	}
+	// Like uploads, finished tasks aren't imported
+	if fields.Status == "done" || fields.Status == "dropped" {
+		return nil
+	}
+
+	fmt.Printf("  %s %s %s\n", info("+"), fields.Title, dim("(new on the server)"))
+	s.created++
+	if s.opts.DryRun {
+		return nil
+	}
+
+	var tags []string
+	for _, category := range strings.Split(rt.todo.text("CATEGORIES"), ",") {
+		if tag := sluggifyKeyword(category); tag != "" && !containsTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	meta := TaskMetadata{
+		Status:    fields.Status,
+		Priority:  fields.Priority,
+		DueDate:   fields.DueDate,
+		StartDate: fields.StartDate,
+	}
+
+	body := rt.todo.text("DESCRIPTION")
+	if body != "" {
+		body += "\n"
+	}
+	task, err := createTaskSilently(s.config, fields.Title, meta, tags, body)
+	if err != nil {
+		return err
+	}
+
+	s.state.Items[task.Note.ID] = &CalDAVSyncItem{
+		UID:  rt.todo.text("UID"),
+		Href: rt.Href,
+		ETag: rt.ETag,
+		Base: localSyncFields(task),
+	}
+	return nil
+}
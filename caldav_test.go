@@ -0,0 +1,409 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const stubCalendarPath = "/calendars/me/tasks/"
+
+// caldavStub is an in-memory calendar collection. It answers PROPFIND and
+// calendar-query REPORTs and honours If-Match / If-None-Match on PUT and
+// DELETE, which is all syncCalDAV needs from a server.
+type caldavStub struct {
+	mu      sync.Mutex
+	objects map[string]*stubObject // by path
+	version int
+}
+
+type stubObject struct {
+	etag string
+	data string
+}
+
+func newCalDAVStub(t *testing.T) (*caldavStub, string) {
+	stub := &caldavStub{objects: make(map[string]*stubObject)}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, server.URL + stubCalendarPath
+}
+
+func (s *caldavStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.Path
+	object := s.objects[path]
+
+	switch r.Method {
+	case "PROPFIND", "REPORT":
+		if path != stubCalendarPath {
+			http.NotFound(w, r)
+			return
+		}
+		s.multistatus(w, r.Method == "REPORT")
+
+	case http.MethodGet:
+		if object == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", object.etag)
+		fmt.Fprint(w, object.data)
+
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && object != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && (object == nil || object.etag != ifMatch) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		status := http.StatusNoContent
+		if object == nil {
+			status = http.StatusCreated
+		}
+		object = s.store(path, string(data))
+		w.Header().Set("ETag", object.etag)
+		w.WriteHeader(status)
+
+	case http.MethodDelete:
+		if object == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && object.etag != ifMatch {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(s.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *caldavStub) store(path, data string) *stubObject {
+	s.version++
+	object := &stubObject{etag: fmt.Sprintf(`"v%d"`, s.version), data: data}
+	s.objects[path] = object
+	return object
+}
+
+func (s *caldavStub) multistatus(w http.ResponseWriter, withData bool) {
+	var paths []string
+	for path := range s.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(w, `<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	for _, path := range paths {
+		object := s.objects[path]
+		fmt.Fprintf(w, "<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>", path, escapeXML(object.etag))
+		if withData {
+			fmt.Fprintf(w, "<c:calendar-data>%s</c:calendar-data>", escapeXML(object.data))
+		}
+		fmt.Fprint(w, "</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>")
+	}
+	fmt.Fprint(w, "</d:multistatus>")
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// put stores a calendar object as another client would
+func (s *caldavStub) put(name, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(stubCalendarPath+name, data)
+}
+
+// edit changes an object as another client would, giving it a new ETag
+func (s *caldavStub) edit(t *testing.T, uid string, edit func(data string) string) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path, object := range s.objects {
+		if strings.Contains(object.data, "UID:"+uid) {
+			s.store(path, edit(object.data))
+			return
+		}
+	}
+	t.Fatalf("no calendar object with UID %s", uid)
+}
+
+func (s *caldavStub) remove(t *testing.T, uid string) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path, object := range s.objects {
+		if strings.Contains(object.data, "UID:"+uid) {
+			delete(s.objects, path)
+			return
+		}
+	}
+	t.Fatalf("no calendar object with UID %s", uid)
+}
+
+// todo returns the VTODO with uid, or false if the server has none
+func (s *caldavStub) todo(uid string) (icsComponent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, object := range s.objects {
+		for _, todo := range parseICSComponents(object.data, "VTODO") {
+			if todo.text("UID") == uid {
+				return todo, true
+			}
+		}
+	}
+	return icsComponent{}, false
+}
+
+func (s *caldavStub) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects)
+}
+
+// newTestVault returns the config of an empty vault in a temporary directory
+func newTestVault(t *testing.T) Config {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("NOTES_CLI_CALDAV_PASSWORD", "")
+
+	notes := dir + "/notes"
+	if err := os.MkdirAll(notes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	denoteIDDirs = []string{notes}
+	idCounter, idCounterOnce = nil, sync.Once{}
+
+	return Config{NotesDir: notes, TaskDir: notes, TOMLConfig: &TOMLConfig{}}
+}
+
+func syncWithStub(t *testing.T, config Config, url, prefer string) {
+	t.Helper()
+	if err := syncCalDAV(config, CalDAVSyncOptions{URL: url, Prefer: prefer}); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+}
+
+func newTestTask(t *testing.T, config Config, title string, meta TaskMetadata) *TaskInfo {
+	t.Helper()
+	task, err := createTaskSilently(config, title, meta, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func reloadTask(t *testing.T, config Config, taskID int) *TaskInfo {
+	t.Helper()
+	task, err := findTaskByID(config, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func uidOf(task *TaskInfo) string {
+	return task.Note.ID + "@notes-cli"
+}
+
+func TestCalDAVSyncCreate(t *testing.T) {
+	config := newTestVault(t)
+	stub, url := newCalDAVStub(t)
+
+	task := newTestTask(t, config, "Write report", TaskMetadata{Status: "paused", Priority: "p1", DueDate: "2026-03-05"})
+	newTestTask(t, config, "Already done", TaskMetadata{Status: "done"})
+	stub.put("remote.ics", "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:remote-1\r\n"+
+		"SUMMARY:Call the bank\r\nSTATUS:IN-PROCESS\r\nPRIORITY:5\r\nDUE;VALUE=DATE:20260310\r\n"+
+		"CATEGORIES:Errands\r\nEND:VTODO\r\nEND:VCALENDAR\r\n")
+
+	syncWithStub(t, config, url, "")
+
+	todo, ok := stub.todo(uidOf(task))
+	if !ok {
+		t.Fatal("task was not uploaded")
+	}
+	if got := todo.text("SUMMARY"); got != "Write report" {
+		t.Errorf("SUMMARY = %q", got)
+	}
+	if got := remoteSyncFields(todo); got.Status != "paused" || got.Priority != "p1" || got.DueDate != "2026-03-05" {
+		t.Errorf("uploaded fields = %+v", got)
+	}
+	if stub.count() != 2 {
+		t.Errorf("server has %d objects, want 2 (done tasks aren't uploaded)", stub.count())
+	}
+
+	tasks, err := findTasks(config, TaskFilters{All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var downloaded *TaskInfo
+	for i := range tasks {
+		if tasks[i].Note.Title == "Call the bank" {
+			downloaded = &tasks[i]
+		}
+	}
+	if downloaded == nil {
+		t.Fatal("server task was not downloaded")
+	}
+	if downloaded.Status != "delegated" || downloaded.Priority != "p2" || downloaded.DueDate != "2026-03-10" || !containsTag(downloaded.Note.Tags, "errands") {
+		t.Errorf("downloaded task = %+v %v", downloaded.TaskMetadata, downloaded.Note.Tags)
+	}
+
+	// A second sync finds nothing to do and keeps the paused status
+	syncWithStub(t, config, url, "")
+	if got := reloadTask(t, config, task.TaskID).Status; got != "paused" {
+		t.Errorf("status after round trip = %q, want paused", got)
+	}
+	if stub.count() != 2 {
+		t.Errorf("server has %d objects after resync, want 2", stub.count())
+	}
+}
+
+func TestCalDAVSyncUpdateBothSides(t *testing.T) {
+	config := newTestVault(t)
+	stub, url := newCalDAVStub(t)
+
+	task := newTestTask(t, config, "Plan offsite", TaskMetadata{Status: "paused", Priority: "p3"})
+	syncWithStub(t, config, url, "")
+
+	// Different fields change on each side
+	if _, err := applyTaskUpdate(config, task.Path, TaskMetadata{Priority: "p1"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	stub.edit(t, uidOf(task), func(data string) string {
+		return strings.Replace(data, "SUMMARY:Plan offsite", "SUMMARY:Plan team offsite", 1)
+	})
+
+	syncWithStub(t, config, url, "")
+
+	local := reloadTask(t, config, task.TaskID)
+	if local.Note.Title != "Plan team offsite" || local.Priority != "p1" || local.Status != "paused" {
+		t.Errorf("local task = %q %+v", local.Note.Title, local.TaskMetadata)
+	}
+	todo, _ := stub.todo(uidOf(task))
+	if got := remoteSyncFields(todo); got.Title != "Plan team offsite" || got.Priority != "p1" || got.Status != "paused" {
+		t.Errorf("server task = %+v", got)
+	}
+
+	// Another client finishing the task replaces the paused status
+	stub.edit(t, uidOf(task), func(data string) string {
+		return strings.Replace(data, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED", 1)
+	})
+	syncWithStub(t, config, url, "")
+	if got := reloadTask(t, config, task.TaskID).Status; got != "done" {
+		t.Errorf("status = %q, want done", got)
+	}
+}
+
+func TestCalDAVSyncDelete(t *testing.T) {
+	config := newTestVault(t)
+	stub, url := newCalDAVStub(t)
+
+	gone := newTestTask(t, config, "Deleted here", TaskMetadata{})
+	kept := newTestTask(t, config, "Deleted there", TaskMetadata{})
+	syncWithStub(t, config, url, "")
+
+	if err := os.Remove(gone.Path); err != nil {
+		t.Fatal(err)
+	}
+	stub.remove(t, uidOf(kept))
+
+	syncWithStub(t, config, url, "")
+
+	if _, ok := stub.todo(uidOf(gone)); ok {
+		t.Error("task deleted locally is still on the server")
+	}
+	if _, err := findTaskByID(config, kept.TaskID); err != nil {
+		t.Errorf("task deleted on the server was deleted locally: %v", err)
+	}
+
+	state, err := loadCalDAVState(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Items[gone.Note.ID]; ok {
+		t.Error("deleted task is still in the sync state")
+	}
+	if item := state.Items[kept.Note.ID]; item == nil || !item.RemoteDeleted {
+		t.Errorf("task deleted on the server isn't marked as such: %+v", item)
+	}
+
+	// It isn't uploaded again
+	syncWithStub(t, config, url, "")
+	if stub.count() != 0 {
+		t.Errorf("server has %d objects, want 0", stub.count())
+	}
+}
+
+func TestCalDAVSyncConflict(t *testing.T) {
+	config := newTestVault(t)
+	stub, url := newCalDAVStub(t)
+
+	task := newTestTask(t, config, "Renew passport", TaskMetadata{DueDate: "2026-04-01"})
+	syncWithStub(t, config, url, "")
+
+	if _, err := applyTaskUpdate(config, task.Path, TaskMetadata{DueDate: "2026-04-15"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	stub.edit(t, uidOf(task), func(data string) string {
+		return strings.Replace(data, "DUE;VALUE=DATE:20260401", "DUE;VALUE=DATE:20260420", 1)
+	})
+
+	// Without -prefer neither side changes
+	syncWithStub(t, config, url, "")
+	if got := reloadTask(t, config, task.TaskID).DueDate; got != "2026-04-15" {
+		t.Errorf("local due date = %s, want it left alone", got)
+	}
+	todo, _ := stub.todo(uidOf(task))
+	if got := remoteSyncFields(todo).DueDate; got != "2026-04-20" {
+		t.Errorf("server due date = %s, want it left alone", got)
+	}
+
+	syncWithStub(t, config, url, "remote")
+	if got := reloadTask(t, config, task.TaskID).DueDate; got != "2026-04-20" {
+		t.Errorf("local due date = %s, want the server's", got)
+	}
+
+	// A stale ETag is reported rather than overwriting the server
+	if _, err := applyTaskUpdate(config, reloadTask(t, config, task.TaskID).Path, TaskMetadata{Priority: "p2"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	state, err := loadCalDAVState(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newCalDAVClient(CalDAVConfig{URL: url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := state.Items[task.Note.ID]
+	if _, err := client.put(item.Href, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", `"stale"`, false); err != errPreconditionFailed {
+		t.Errorf("put with a stale ETag: %v, want errPreconditionFailed", err)
+	}
+}
@@ -0,0 +1,599 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Command is a notes-cli command. A command runs itself, dispatches to its
+// subcommands, or both ('conflicts' lists, 'conflicts resolve' resolves).
+type Command struct {
+	Name        string
+	Args        string // positional arguments, for help
+	Summary     string
+	Hidden      bool
+	MinArgs     int    // positional arguments required
+	JSON        bool   // supports -format json
+	AllProfiles bool   // only reads, so it can run with -all-profiles
+	NoConfig    bool   // runs without loading the config
+	RawArgs     bool   // gets its arguments without flag parsing
+	Doing       string // what failed, for "Error <doing>: ..."
+
+	// Completion: what each positional argument is (the last repeats) and
+	// what flags take, beyond the defaults in flagCompletions. Each is a
+	// kind such as "task" or "file", or choices like "p1|p2|p3".
+	ArgKinds   []string
+	FlagValues map[string]string
+
+	// Setup defines the command's flags and returns the function that
+	// runs it. Help and completions call it to see the flags.
+	Setup       func(fs *flag.FlagSet) func(ctx *Context) error
+	Subcommands []*Command
+}
+
+// Context is what a command runs with
+type Context struct {
+	Config Config
+	Args   []string // positional arguments
+	Format string
+}
+
+// JSON reports whether the command should print JSON
+func (ctx *Context) JSON() bool {
+	return ctx.Format == "json"
+}
+
+// GlobalFlags work with every command, before or after it
+type GlobalFlags struct {
+	Dir     string
+	NoColor bool
+	Format  string
+}
+
+var globalFlags = GlobalFlags{Format: "text"}
+
+// addGlobalFlags defines the global flags on fs. Every command's flag set
+// gets them, bound to the same variables.
+func addGlobalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&globalFlags.Dir, "dir", globalFlags.Dir, "Use this `directory` for notes and tasks, ignoring the config")
+	fs.BoolVar(&globalFlags.NoColor, "no-color", globalFlags.NoColor, "Disable colored output")
+	fs.StringVar(&globalFlags.Format, "format", globalFlags.Format, "Output `format`: text or json (list and search commands)")
+	fs.StringVar(&selectedProfile, "profile", selectedProfile, "Use the `name`d profile from config.toml")
+	fs.BoolVar(&allProfiles, "all-profiles", allProfiles, "Run a list or search command against every profile")
+}
+
+func isGlobalFlag(name string) bool {
+	switch name {
+	case "dir", "no-color", "format", "profile", "all-profiles":
+		return true
+	}
+	return false
+}
+
+// commandAliases are the old top-level commands, still accepted
+var commandAliases = map[string][]string{
+	"tasks":         {"task", "list"},
+	"done":          {"task", "done"},
+	"task-done":     {"task", "done"},
+	"task-update":   {"task", "update"},
+	"projects":      {"project", "list"},
+	"project-tasks": {"project", "tasks"},
+	"new":           {"note", "new"},
+	"ls":            {"note", "list"},
+	"list":          {"note", "list"},
+	"edit":          {"note", "edit"},
+	"rename":        {"note", "rename"},
+	"backlinks":     {"note", "backlinks"},
+}
+
+func findCommand(list []*Command, name string) *Command {
+	for _, cmd := range list {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// lookupCommand follows args down the command tree, expanding aliases. It
+// returns the deepest command matched, its path and the remaining args.
+func lookupCommand(args []string) (*Command, []string, []string) {
+	if len(args) == 0 {
+		return nil, nil, args
+	}
+	if alias, ok := commandAliases[args[0]]; ok {
+		args = append(append([]string{}, alias...), args[1:]...)
+	}
+
+	cmd := findCommand(commands, args[0])
+	if cmd == nil {
+		return nil, nil, args
+	}
+	path := []string{cmd.Name}
+	args = args[1:]
+	for len(args) > 0 {
+		sub := findCommand(cmd.Subcommands, args[0])
+		if sub == nil {
+			break
+		}
+		cmd = sub
+		path = append(path, sub.Name)
+		args = args[1:]
+	}
+	return cmd, path, args
+}
+
+// optionalValue is a flag used alone or with a value in the next argument,
+// like -soon [N]
+type optionalValue interface {
+	flag.Value
+	IsBoolFlag() bool
+	IsValue(arg string) bool
+}
+
+// soonFlag is -soon [N]: N days, or the soon_horizon setting when bare
+type soonFlag int
+
+func (s *soonFlag) String() string {
+	if s == nil || *s == 0 {
+		return ""
+	}
+	if *s < 0 {
+		return "true"
+	}
+	return strconv.Itoa(int(*s))
+}
+
+func (s *soonFlag) Set(value string) error {
+	switch value {
+	case "true":
+		*s = -1
+		return nil
+	case "false":
+		*s = 0
+		return nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		return fmt.Errorf("must be a number of days")
+	}
+	*s = soonFlag(days)
+	return nil
+}
+
+func (s *soonFlag) IsBoolFlag() bool { return true }
+
+func (s *soonFlag) IsValue(arg string) bool {
+	days, err := strconv.Atoi(arg)
+	return err == nil && days > 0
+}
+
+// days returns the horizon in days, 0 when -soon wasn't given
+func (s *soonFlag) days(config Config) int {
+	if *s < 0 {
+		return config.TOMLConfig.SoonHorizon
+	}
+	return int(*s)
+}
+
+// jsonFlag is the older -json, short for -format json
+type jsonFlag struct{}
+
+func (jsonFlag) String() string   { return "false" }
+func (jsonFlag) IsBoolFlag() bool { return true }
+
+func (jsonFlag) Set(value string) error {
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if on {
+		globalFlags.Format = "json"
+	}
+	return nil
+}
+
+// parseArgs parses flags found anywhere among args and returns the
+// positional arguments. "--" ends the flags.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		// "-" alone is an argument (stdin)
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			// Parse reports it
+			continue
+		}
+		if opt, ok := f.Value.(optionalValue); ok {
+			if i+1 < len(args) && opt.IsValue(args[i+1]) {
+				flags[len(flags)-1] = arg + "=" + args[i+1]
+				i++
+			}
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if i+1 < len(args) {
+			flags = append(flags, args[i+1])
+			i++
+		}
+	}
+
+	if err := fs.Parse(flags); err != nil {
+		return nil, err
+	}
+	return positional, nil
+}
+
+// commandFlags returns a command's flag set and run function
+func commandFlags(cmd *Command, path []string) (*flag.FlagSet, func(ctx *Context) error) {
+	fs := flag.NewFlagSet("notes-cli "+strings.Join(path, " "), flag.ContinueOnError)
+	var run func(ctx *Context) error
+	if cmd.Setup != nil {
+		run = cmd.Setup(fs)
+	}
+	addGlobalFlags(fs)
+	fs.Usage = func() { printCommandHelp(cmd, path, fs) }
+	return fs, run
+}
+
+// runCommand parses the command line and runs the command, returning the
+// exit status
+func runCommand(args []string) int {
+	commandLine := args
+	global := flag.NewFlagSet("notes-cli", flag.ContinueOnError)
+	addGlobalFlags(global)
+	global.Usage = printUsage
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	args = global.Args()
+	if len(args) == 0 {
+		printUsage()
+		return 1
+	}
+
+	cmd, path, args := lookupCommand(args)
+	if cmd == nil {
+		fmt.Printf("Unknown command: %s\n\n", args[0])
+		printUsage()
+		return 1
+	}
+
+	if cmd.Setup == nil {
+		switch {
+		case len(args) == 0:
+			fmt.Printf("Error: %s subcommand required\n\n", strings.Join(path, " "))
+		case args[0] == "-h" || args[0] == "-help" || args[0] == "--help":
+			printGroupHelp(cmd, path)
+			return 0
+		default:
+			fmt.Printf("Unknown %s subcommand: %s\n\n", strings.Join(path, " "), args[0])
+		}
+		printGroupHelp(cmd, path)
+		return 1
+	}
+
+	fs, run := commandFlags(cmd, path)
+	positional := args
+	if !cmd.RawArgs {
+		var err error
+		if positional, err = parseArgs(fs, args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			return 2
+		}
+	}
+
+	if globalFlags.NoColor {
+		colorEnabled = false
+	}
+
+	usage := "Usage: notes-cli " + commandSynopsis(cmd, path)
+	switch {
+	case len(positional) < cmd.MinArgs:
+		fmt.Printf("Error: %s required\n%s\n", cmd.Args, usage)
+		return 1
+	case cmd.Args == "" && len(positional) > 0:
+		fmt.Printf("Error: unexpected argument %q\n%s\n", positional[0], usage)
+		return 1
+	case globalFlags.Format != "text" && globalFlags.Format != "json":
+		fmt.Printf("Error: unknown format %q (use text or json)\n", globalFlags.Format)
+		return 1
+	case globalFlags.Format == "json" && !cmd.JSON:
+		fmt.Printf("Error: notes-cli %s has no JSON output\n", strings.Join(path, " "))
+		return 1
+	case allProfiles && selectedProfile != "":
+		fmt.Println("Error: use either -profile or -all-profiles")
+		return 1
+	case watchMode && (allProfiles || globalFlags.Format == "json"):
+		fmt.Println("Error: -watch doesn't work with -all-profiles or -format json")
+		return 1
+	}
+
+	ctx := &Context{Args: positional, Format: globalFlags.Format}
+	if !cmd.NoConfig {
+		ctx.Config = loadConfig()
+	}
+
+	if allProfiles {
+		if !cmd.AllProfiles {
+			fmt.Println("Error: -all-profiles only works with list and search commands")
+			return 1
+		}
+		if err := runAllProfiles(ctx.Config, withoutAllProfiles(commandLine), ctx.JSON()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if err := run(ctx); err != nil {
+		if cmd.Doing != "" {
+			fmt.Printf("Error %s: %v\n", cmd.Doing, err)
+		} else {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// withoutAllProfiles drops -all-profiles so each profile's run is an
+// ordinary one
+func withoutAllProfiles(args []string) []string {
+	var result []string
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "all-profiles" {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// printJSON writes v as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// Help
+
+// commandSynopsis is a command's path, arguments and flags placeholder
+func commandSynopsis(cmd *Command, path []string) string {
+	parts := append([]string{}, path...)
+	if cmd.Setup == nil {
+		parts = append(parts, "<command>")
+	}
+	if cmd.Args != "" {
+		parts = append(parts, cmd.Args)
+	}
+	if cmd.Setup != nil {
+		parts = append(parts, "[flags]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// visibleCommands walks the command tree, calling fn for every command
+// that runs
+func visibleCommands(list []*Command, path []string, fn func(cmd *Command, path []string)) {
+	for _, cmd := range list {
+		if cmd.Hidden {
+			continue
+		}
+		cmdPath := append(append([]string{}, path...), cmd.Name)
+		if cmd.Setup != nil {
+			fn(cmd, cmdPath)
+		}
+		visibleCommands(cmd.Subcommands, cmdPath, fn)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  notes-cli [global flags] <command> [args] [flags]")
+	fmt.Println()
+	fmt.Println("Flags can go before or after the arguments; '--' ends them.")
+	fmt.Println()
+
+	width := 0
+	visibleCommands(commands, nil, func(cmd *Command, path []string) {
+		if n := len(strings.Join(path, " ")) + len(cmd.Args) + 1; n > width {
+			width = n
+		}
+	})
+
+	fmt.Println("Commands:")
+	printed, prevGroup := false, false
+	for _, top := range commands {
+		if top.Hidden {
+			continue
+		}
+		// Commands with subcommands get a paragraph each
+		isGroup := len(top.Subcommands) > 0
+		if printed && (isGroup || prevGroup) {
+			fmt.Println()
+		}
+		printed, prevGroup = true, isGroup
+		visibleCommands([]*Command{top}, nil, func(cmd *Command, path []string) {
+			name := strings.TrimSpace(strings.Join(path, " ") + " " + cmd.Args)
+			fmt.Printf("  %-*s  %s\n", width, name, cmd.Summary)
+		})
+	}
+	fmt.Println()
+
+	fmt.Println("Global flags:")
+	global := flag.NewFlagSet("notes-cli", flag.ContinueOnError)
+	addGlobalFlags(global)
+	printFlags(global, func(name string) bool { return true })
+	fmt.Println()
+
+	fmt.Println("Task arguments:")
+	fmt.Println("  Single:  28")
+	fmt.Println("  Range:   3-5")
+	fmt.Println("  List:    3,5,7")
+	fmt.Println("  Mixed:   3,5-7,10")
+	fmt.Println()
+	fmt.Println("Date formats:")
+	fmt.Println("  Days:     monday, tuesday, fri (next occurrence)")
+	fmt.Println("  Relative: 3d (3 days), 2w (2 weeks), 1m (1 month)")
+	fmt.Println("  Keywords: today, tomorrow, yesterday, next week, next month")
+	fmt.Println("  Absolute: 2024-12-25")
+	fmt.Println()
+
+	fmt.Println("Backward compatibility:")
+	var aliases []string
+	for alias := range commandAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		fmt.Printf("  %-14s %s\n", alias, strings.Join(commandAliases[alias], " "))
+	}
+	fmt.Println()
+
+	fmt.Println("Environment variables:")
+	fmt.Println("  NOTES_DIR - Directory to store notes (default: ~/notes)")
+	fmt.Println("  NOTES_PROFILE - Profile to use (default: default_profile)")
+	fmt.Println()
+	fmt.Println("Run 'notes-cli help <command>' for a command's flags.")
+}
+
+// printCommandHelp shows a command's usage and flags
+func printCommandHelp(cmd *Command, path []string, fs *flag.FlagSet) {
+	fmt.Printf("Usage: notes-cli %s\n", commandSynopsis(cmd, path))
+	if cmd.Summary != "" {
+		fmt.Printf("\n%s\n", cmd.Summary)
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(f *flag.Flag) {
+		if !isGlobalFlag(f.Name) {
+			hasFlags = true
+		}
+	})
+	if hasFlags {
+		fmt.Println("\nFlags:")
+		printFlags(fs, func(name string) bool { return !isGlobalFlag(name) })
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		fmt.Println()
+		printSubcommands(cmd, path)
+	}
+
+	fmt.Println("\nGlobal flags -dir, -format, -no-color, -profile and -all-profiles also")
+	fmt.Println("work here; see 'notes-cli help'.")
+}
+
+// printGroupHelp shows the subcommands of a command that doesn't run itself
+func printGroupHelp(cmd *Command, path []string) {
+	fmt.Printf("Usage: notes-cli %s\n", commandSynopsis(cmd, path))
+	if cmd.Summary != "" {
+		fmt.Printf("\n%s\n", cmd.Summary)
+	}
+	fmt.Println()
+	printSubcommands(cmd, path)
+}
+
+func printSubcommands(cmd *Command, path []string) {
+	var subs []*Command
+	width := 0
+	for _, sub := range cmd.Subcommands {
+		if sub.Hidden {
+			continue
+		}
+		subs = append(subs, sub)
+		if n := len(sub.Name) + len(sub.Args) + 1; n > width {
+			width = n
+		}
+	}
+
+	fmt.Println("Commands:")
+	for _, sub := range subs {
+		fmt.Printf("  %-*s  %s\n", width, strings.TrimSpace(sub.Name+" "+sub.Args), sub.Summary)
+	}
+	fmt.Printf("\nRun 'notes-cli help %s <command>' for a command's flags.\n", strings.Join(path, " "))
+}
+
+// printFlags lists the flags in fs that show accepts, like
+// flag.PrintDefaults but on stdout and aligned
+func printFlags(fs *flag.FlagSet, show func(name string) bool) {
+	type line struct{ name, usage string }
+	var lines []line
+	width := 0
+	fs.VisitAll(func(f *flag.Flag) {
+		if !show(f.Name) {
+			return
+		}
+		valueName, usage := flag.UnquoteUsage(f)
+		name := "-" + f.Name
+		if _, ok := f.Value.(optionalValue); ok {
+			name += " [" + valueName + "]"
+		} else if valueName != "" {
+			name += " " + valueName
+		}
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" && f.DefValue != "0s" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		lines = append(lines, line{name, usage})
+		if len(name) > width {
+			width = len(name)
+		}
+	})
+	for _, l := range lines {
+		fmt.Printf("  %-*s  %s\n", width, l.name, l.usage)
+	}
+}
+
+// helpCommand shows usage for the whole CLI or one command
+func helpCommand(ctx *Context) error {
+	if len(ctx.Args) == 0 {
+		printUsage()
+		return nil
+	}
+
+	cmd, path, rest := lookupCommand(ctx.Args)
+	if cmd == nil || len(rest) > 0 {
+		return fmt.Errorf("unknown command: %s", strings.Join(ctx.Args, " "))
+	}
+	if cmd.Setup == nil {
+		printGroupHelp(cmd, path)
+		return nil
+	}
+	fs, _ := commandFlags(cmd, path)
+	printCommandHelp(cmd, path, fs)
+	return nil
+}
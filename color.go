@@ -43,11 +43,20 @@ func init() {
 	}
 	
 	// Check if stdout is a terminal
-	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) == 0 {
+	if !isTerminal(os.Stdout) {
 		colorEnabled = false
 	}
 }
 
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (fileInfo.Mode() & os.ModeCharDevice) != 0
+}
+
 // Color functions
 func color(code, text string) string {
 	if !colorEnabled {
@@ -126,32 +135,45 @@ func info(text string) string {
 }
 
 func priority(p string) string {
+	return priorityStyle(p)("[" + strings.ToUpper(p) + "]")
+}
+
+// priorityStyle colors text the way priority colors p
+func priorityStyle(p string) func(string) string {
 	switch p {
 	case "p1":
-		return brightRed(bold("[P1]"))
+		return func(text string) string { return brightRed(bold(text)) }
 	case "p2":
-		return yellow("[P2]")
+		return yellow
 	case "p3":
-		return blue("[P3]")
+		return blue
 	default:
-		return gray("[" + strings.ToUpper(p) + "]")
+		return gray
 	}
 }
 
 func status(s string) string {
+	switch s {
+	case "done", "open", "paused", "delegated", "dropped":
+		return statusStyle(s)(getStatusIcon(s))
+	default:
+		return gray("?")
+	}
+}
+
+// statusStyle colors text the way status colors s
+func statusStyle(s string) func(string) string {
 	switch s {
 	case "done":
-		return green("✓")
+		return green
 	case "open":
-		return cyan("○")
+		return cyan
 	case "paused":
-		return yellow("⏸")
+		return yellow
 	case "delegated":
-		return blue("→")
-	case "dropped":
-		return gray("✗")
+		return blue
 	default:
-		return gray("?")
+		return gray
 	}
 }
 
@@ -0,0 +1,940 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errTitleRequired = errors.New("title is required")
+
+// commands is the command tree: dispatch, help and completions all come
+// from it. It's filled in by init because help refers back to it.
+var commands []*Command
+
+func init() {
+	commands = []*Command{
+		taskCommands(),
+		projectCommands(),
+		noteCommands(),
+		{
+			Name:        "search",
+			Args:        "<query>",
+			Summary:     "Full-text search (\"phrase\", OR, -exclude)",
+			FlagValues:  map[string]string{"type": "task|project|note"},
+			MinArgs:     1,
+			JSON:        true,
+			AllProfiles: true,
+			Doing:       "searching",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				noteType := fs.String("type", "", "Limit to type: task, project, note")
+				limit := fs.Int("limit", 20, "Maximum number of results (0 for all)")
+				fs.Var(jsonFlag{}, "json", "Output results as JSON (same as -format json)")
+				rebuild := fs.Bool("rebuild", false, "Rebuild the search index from scratch")
+				return func(ctx *Context) error {
+					return runSearch(ctx.Config, SearchOptions{
+						Query:   strings.Join(ctx.Args, " "),
+						Type:    *noteType,
+						Limit:   *limit,
+						JSON:    ctx.JSON(),
+						Rebuild: *rebuild,
+					})
+				}
+			},
+		},
+		{
+			Name:    "journal",
+			Args:    "[date]",
+			Summary: "Open (or create) the journal note for a day",
+			Doing:   "opening journal",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				withTasks := fs.Bool("tasks", false, "Add (or refresh) the day's due, overdue and completed tasks")
+				noEdit := fs.Bool("no-edit", false, "Skip opening editor")
+				return func(ctx *Context) error {
+					// Optional date argument (default: today)
+					return journal(ctx.Config, strings.Join(ctx.Args, " "), *withTasks, *noEdit)
+				}
+			},
+		},
+		{
+			Name:    "tui",
+			Summary: "Full-screen task, project and note browser",
+			Doing:   "running tui",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				return func(ctx *Context) error {
+					return runTUI(ctx.Config)
+				}
+			},
+		},
+		{
+			Name:    "serve",
+			Summary: "Serve tasks, projects and notes as a JSON API",
+			Doing:   "serving",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				addr := fs.String("addr", "127.0.0.1:8080", "Address to listen on")
+				token := fs.String("token", "", "Require this bearer token (default: $NOTES_CLI_TOKEN)")
+				feed := fs.String("feed", "", "Publish this calendar file (from 'export ics -o') at /calendar.ics")
+				return func(ctx *Context) error {
+					return serve(ctx.Config, ServeOptions{Addr: *addr, Token: *token, Feed: *feed})
+				}
+			},
+		},
+		{
+			Name:       "remind",
+			Summary:    "Send reminders for due tasks (once, for cron, or -daemon)",
+			FlagValues: map[string]string{"notify": strings.Join(notifierNames, "|")},
+			Doing:      "sending reminders",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				daemon := fs.Bool("daemon", false, "Keep running, checking every -interval")
+				interval := fs.Duration("interval", 0, "How often the daemon checks (default: [remind] interval)")
+				notify := fs.String("notify", "", "Notifiers to use, comma-separated (default: [remind] notifiers)")
+				late := fs.Duration("late", 24*time.Hour, "Still send reminders missed by up to this long")
+				dryRun := fs.Bool("dry-run", false, "Show what would be sent without sending it")
+				list := fs.Bool("list", false, "List upcoming and recently sent reminders")
+				return func(ctx *Context) error {
+					if *interval < 0 {
+						return fmt.Errorf("invalid -interval %s", *interval)
+					}
+					var notifiers []string
+					if *notify != "" {
+						notifiers = parseTags(*notify)
+					}
+					return remind(ctx.Config, RemindOptions{
+						Daemon:    *daemon,
+						Interval:  *interval,
+						Notifiers: notifiers,
+						Late:      *late,
+						DryRun:    *dryRun,
+						List:      *list,
+					})
+				}
+			},
+		},
+		{
+			Name:     "history",
+			Args:     "<task-id|note|denote-id>",
+			Summary:  "Show a file's changes from git log",
+			ArgKinds: []string{"task"},
+			MinArgs:  1,
+			Doing:    "showing history",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				isProject := fs.Bool("project", false, "Treat the reference as a project")
+				patch := fs.Bool("patch", false, "Show the changes made by each commit")
+				return func(ctx *Context) error {
+					return showHistory(ctx.Config, ctx.Args[0], *isProject, *patch)
+				}
+			},
+		},
+		{
+			Name:    "config",
+			Summary: "Show the effective config, or create a config file",
+			Subcommands: []*Command{
+				{
+					Name:    "show",
+					Summary: "Show the effective config and where each value comes from",
+					Doing:   "showing config",
+					Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+						return func(ctx *Context) error {
+							return showConfig(ctx.Config)
+						}
+					},
+				},
+				{
+					Name:     "init",
+					Summary:  "Create a commented config file",
+					NoConfig: true,
+					Doing:    "creating config",
+					Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+						local := fs.Bool("local", false, "Create .notes-cli.toml in the current directory instead")
+						return func(ctx *Context) error {
+							return initConfig(*local)
+						}
+					},
+				},
+			},
+		},
+		{
+			Name:        "conflicts",
+			Summary:     "Show sync conflict copies",
+			AllProfiles: true,
+			Doing:       "listing conflicts",
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				return func(ctx *Context) error {
+					return listSyncConflicts(ctx.Config)
+				}
+			},
+			Subcommands: []*Command{
+				{
+					Name:     "resolve",
+					Args:     "<n|file>",
+					Summary:  "Keep one version of a conflict, or merge their logs",
+					ArgKinds: []string{"conflict"},
+					MinArgs:  1,
+					Doing:    "resolving conflict",
+					Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+						keep := fs.String("keep", "", "Version to keep: original, conflict, or merge (original plus the conflict's log entries)")
+						return func(ctx *Context) error {
+							return resolveSyncConflict(ctx.Config, ctx.Args[0], *keep)
+						}
+					},
+				},
+			},
+		},
+		reportCommands(),
+		exportCommands(),
+		importCommands(),
+		{
+			Name:    "sync",
+			Summary: "Two-way task sync",
+			Subcommands: []*Command{
+				{
+					Name:    "caldav",
+					Summary: "Two-way task sync with a CalDAV server",
+					Doing:   "syncing",
+					Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+						url := fs.String("url", "", "CalDAV task collection URL (default: [caldav] url in config.toml)")
+						user := fs.String("user", "", "CalDAV username (default: [caldav] username)")
+						dryRun := fs.Bool("dry-run", false, "Show what would change without changing anything")
+						prefer := fs.String("prefer", "", "Resolve conflicting edits in favour of: local or remote")
+						return func(ctx *Context) error {
+							return syncCalDAV(ctx.Config, CalDAVSyncOptions{
+								URL:      *url,
+								Username: *user,
+								DryRun:   *dryRun,
+								Prefer:   *prefer,
+							})
+						}
+					},
+				},
+			},
+		},
+		{
+			Name:     "completion",
+			Args:     "<bash|zsh|fish|powershell>",
+			Summary:  "Print a shell completion script",
+			MinArgs:  1,
+			NoConfig: true,
+			ArgKinds: []string{"bash|zsh|fish|powershell"},
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				return func(ctx *Context) error {
+					return printCompletionScript(ctx.Args[0])
+				}
+			},
+		},
+		{
+			Name:     "__complete",
+			Args:     "<n> [words]",
+			Summary:  "Complete the nth word of a command line (used by the completion scripts)",
+			Hidden:   true,
+			NoConfig: true,
+			RawArgs:  true,
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				return completeCommand
+			},
+		},
+		{
+			Name:     "help",
+			Args:     "[command]",
+			Summary:  "Show usage for notes-cli or a command",
+			ArgKinds: []string{"command"},
+			NoConfig: true,
+			Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+				return helpCommand
+			},
+		},
+	}
+}
+
+func taskCommands() *Command {
+	return &Command{
+		Name:    "task",
+		Summary: "Create, list and change tasks",
+		Subcommands: []*Command{
+			{
+				Name:    "new",
+				Args:    "<title>",
+				Summary: "Create a new task",
+				Doing:   "creating task",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					title := fs.String("title", "", "Task title")
+					priority := fs.String("p", "", "Priority (p1, p2, p3)")
+					due := fs.String("due", "", "Due date (YYYY-MM-DD or 'today', 'tomorrow', 'next week')")
+					start := fs.String("start", "", "Start date")
+					estimate := fs.Int("estimate", 0, "Estimate (fibonacci: 1,2,3,5,8,13)")
+					project := fs.String("project", "", "Project name")
+					area := fs.String("area", "", "Area (e.g., work, personal, home)")
+					assignee := fs.String("assign", "", "Assignee")
+					tags := fs.String("tags", "", "Additional tags (comma-separated)")
+					remind := fs.String("remind", "", "Reminders (e.g. -1d, 09:00, '-1d 17:00'; comma-separated)")
+					templateName := fs.String("template", "", "Template from ~/.config/notes-cli/templates/")
+					noEdit := fs.Bool("no-edit", false, "Skip opening editor")
+					return func(ctx *Context) error {
+						// Support positional argument for title
+						if *title == "" {
+							*title = strings.Join(ctx.Args, " ")
+						}
+						if *title == "" {
+							return errTitleRequired
+						}
+
+						dueDate, err := parseDate(*due)
+						if err != nil {
+							return err
+						}
+						startDate, err := parseDate(*start)
+						if err != nil {
+							return err
+						}
+						if err := checkRemind(*remind); err != nil {
+							return err
+						}
+
+						meta := TaskMetadata{
+							Priority:  *priority,
+							DueDate:   dueDate,
+							StartDate: startDate,
+							Estimate:  *estimate,
+							Project:   *project,
+							Area:      *area,
+							Assignee:  *assignee,
+							Remind:    *remind,
+						}
+						extraTags := parseTags(*tags)
+
+						body, err := applyTaskTemplate(ctx.Config, *templateName, *title, &meta, &extraTags)
+						if err != nil {
+							return err
+						}
+						return createTask(ctx.Config, *title, meta, extraTags, body, *noEdit)
+					}
+				},
+			},
+			{
+				Name:        "list",
+				Summary:     "List tasks (default: open tasks)",
+				JSON:        true,
+				AllProfiles: true,
+				Doing:       "listing tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					addWatchFlag(fs)
+					return func(ctx *Context) error {
+						filters := filterFlags.filters(ctx.Config)
+						if ctx.JSON() {
+							return printTasksJSON(ctx.Config, filters)
+						}
+						if watchMode {
+							return watch(ctx.Config, func() error {
+								return listTasks(ctx.Config, filters)
+							})
+						}
+						if err := listTasks(ctx.Config, filters); err != nil {
+							return err
+						}
+						warnSyncConflicts(ctx.Config)
+						return nil
+					}
+				},
+			},
+			{
+				Name:       "board",
+				Summary:    "Show tasks as kanban columns (default: by status)",
+				FlagValues: map[string]string{"group": strings.Join(boardGroups, "|")},
+				Doing:      "showing board",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					group := fs.String("group", "status", "Columns by: status, priority, assignee, area")
+					width := fs.Int("width", 0, "Board width (default: terminal width)")
+					addWatchFlag(fs)
+					return func(ctx *Context) error {
+						// The board picks its own default statuses (see taskBoard)
+						filters := filterFlags.filters(ctx.Config)
+						filters.Status = *filterFlags.status
+						opts := BoardOptions{Group: *group, Width: *width}
+						if watchMode {
+							return watch(ctx.Config, func() error {
+								return taskBoard(ctx.Config, filters, opts)
+							})
+						}
+						return taskBoard(ctx.Config, filters, opts)
+					}
+				},
+			},
+			{
+				Name:     "done",
+				Args:     "<tasks>",
+				Summary:  "Mark task(s) as done",
+				ArgKinds: []string{"task"},
+				MinArgs:  1,
+				Doing:    "marking task done",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return markTasksDone(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+			{
+				Name:     "update",
+				Args:     "<tasks>",
+				Summary:  "Update task(s)",
+				ArgKinds: []string{"task"},
+				MinArgs:  1,
+				Doing:    "updating task",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					status := fs.String("status", "", "New status (open, done, paused, delegated, dropped)")
+					priority := fs.String("p", "", "New priority (p1, p2, p3)")
+					due := fs.String("due", "", "New due date")
+					start := fs.String("start", "", "New start date")
+					estimate := fs.Int("estimate", 0, "New estimate")
+					project := fs.String("project", "", "New project")
+					area := fs.String("area", "", "New area")
+					assignee := fs.String("assign", "", "New assignee")
+					tags := fs.String("tags", "", "Add/remove tags (use -tag to remove)")
+					remind := fs.String("remind", "", "New reminders ('none' to turn off)")
+					return func(ctx *Context) error {
+						dueDate, err := parseDate(*due)
+						if err != nil {
+							return err
+						}
+						startDate, err := parseDate(*start)
+						if err != nil {
+							return err
+						}
+						if err := checkRemind(*remind); err != nil {
+							return err
+						}
+
+						updates := TaskMetadata{
+							Status:    *status,
+							Priority:  *priority,
+							DueDate:   dueDate,
+							StartDate: startDate,
+							Estimate:  *estimate,
+							Project:   *project,
+							Area:      *area,
+							Assignee:  *assignee,
+							Remind:    *remind,
+						}
+						return updateTasks(ctx.Config, ctx.Args[0], updates, *tags)
+					}
+				},
+			},
+			{
+				Name:     "edit",
+				Args:     "<task>",
+				Summary:  "Edit a task file",
+				ArgKinds: []string{"task"},
+				MinArgs:  1,
+				Doing:    "editing task",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return editTask(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+			{
+				Name:     "show",
+				Args:     "<task>",
+				Summary:  "Show a task with its links",
+				ArgKinds: []string{"task"},
+				MinArgs:  1,
+				Doing:    "showing task",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return showTask(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+			{
+				Name:     "log",
+				Args:     "<task> <message>",
+				Summary:  "Add a timestamped log entry",
+				ArgKinds: []string{"task", ""},
+				MinArgs:  2,
+				Doing:    "adding log entry",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return logToTask(ctx.Config, ctx.Args[0], strings.Join(ctx.Args[1:], " "))
+					}
+				},
+			},
+			{
+				Name:     "delete",
+				Args:     "<tasks>",
+				Summary:  "Delete task(s) permanently",
+				ArgKinds: []string{"task"},
+				MinArgs:  1,
+				Doing:    "deleting task",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return deleteTasks(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+		},
+	}
+}
+
+func projectCommands() *Command {
+	return &Command{
+		Name:    "project",
+		Summary: "Create, list and change projects",
+		Subcommands: []*Command{
+			{
+				Name:       "new",
+				Args:       "<title>",
+				Summary:    "Create a new project",
+				FlagValues: map[string]string{"status": "active|paused|completed|cancelled"},
+				Doing:      "creating project",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					title := fs.String("title", "", "Project title")
+					status := fs.String("status", "", "Project status (active, completed, paused, cancelled)")
+					priority := fs.String("p", "", "Priority (p1, p2, p3)")
+					due := fs.String("due", "", "Due date")
+					start := fs.String("start", "", "Start date")
+					area := fs.String("area", "", "Area (work, personal)")
+					tags := fs.String("tags", "", "Additional tags (comma-separated)")
+					templateName := fs.String("template", "", "Template from ~/.config/notes-cli/templates/")
+					noEdit := fs.Bool("no-edit", false, "Skip opening editor")
+					return func(ctx *Context) error {
+						// Support positional argument for title
+						if *title == "" {
+							*title = strings.Join(ctx.Args, " ")
+						}
+						if *title == "" {
+							return errTitleRequired
+						}
+
+						dueDate, err := parseDate(*due)
+						if err != nil {
+							return err
+						}
+						startDate, err := parseDate(*start)
+						if err != nil {
+							return err
+						}
+
+						meta := ProjectMetadata{
+							Status:    *status,
+							Priority:  *priority,
+							DueDate:   dueDate,
+							StartDate: startDate,
+							Area:      *area,
+						}
+						extraTags := parseTags(*tags)
+
+						body, err := applyProjectTemplate(ctx.Config, *templateName, *title, &meta, &extraTags)
+						if err != nil {
+							return err
+						}
+						return createProject(ctx.Config, *title, meta, extraTags, body, *noEdit)
+					}
+				},
+			},
+			{
+				Name:    "list",
+				Summary: "List projects (default: active only)",
+				FlagValues: map[string]string{
+					"status": "active|paused|completed|cancelled",
+					"sort":   "modified|priority|due|created|name|area",
+				},
+				JSON:        true,
+				AllProfiles: true,
+				Doing:       "listing projects",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					status := fs.String("status", "", "Filter by status (active, completed, paused, cancelled)")
+					all := fs.Bool("all", false, "Show all projects (default: active only)")
+					var soon soonFlag
+					fs.Var(&soon, "soon", "Show projects due within `N` days (default: soon_horizon)")
+					sortBy := fs.String("sort", "modified", "Sort by: modified, priority, due, created, name, area")
+					reverse := fs.Bool("reverse", false, "Reverse sort order")
+					addWatchFlag(fs)
+					return func(ctx *Context) error {
+						filters := ProjectFilters{
+							Status:   *status,
+							All:      *all,
+							SoonDays: soon.days(ctx.Config),
+							SortBy:   *sortBy,
+							Reverse:  *reverse,
+						}
+						if ctx.JSON() {
+							return printProjectsJSON(ctx.Config, filters)
+						}
+						if watchMode {
+							return watch(ctx.Config, func() error {
+								return listProjects(ctx.Config, filters)
+							})
+						}
+						if err := listProjects(ctx.Config, filters); err != nil {
+							return err
+						}
+						warnSyncConflicts(ctx.Config)
+						return nil
+					}
+				},
+			},
+			{
+				Name:        "tasks",
+				Args:        "<project>",
+				Summary:     "List tasks for a project",
+				ArgKinds:    []string{"project"},
+				MinArgs:     1,
+				AllProfiles: true,
+				Doing:       "listing project tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					sortBy := fs.String("sort", "priority", "Sort by: modified, priority, due, created, start, estimate")
+					reverse := fs.Bool("reverse", false, "Reverse sort order")
+					return func(ctx *Context) error {
+						return projectTasksWithSort(ctx.Config, strings.Join(ctx.Args, " "), *sortBy, *reverse)
+					}
+				},
+			},
+			{
+				Name:       "update",
+				Args:       "<projects>",
+				Summary:    "Update project(s)",
+				ArgKinds:   []string{"project"},
+				FlagValues: map[string]string{"status": "active|paused|completed|cancelled"},
+				MinArgs:    1,
+				Doing:      "updating project",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					status := fs.String("status", "", "New status (active, completed, paused, cancelled)")
+					priority := fs.String("p", "", "New priority (p1, p2, p3)")
+					due := fs.String("due", "", "New due date")
+					start := fs.String("start", "", "New start date")
+					area := fs.String("area", "", "New area")
+					tags := fs.String("tags", "", "Add/remove tags (use -tag to remove)")
+					return func(ctx *Context) error {
+						dueDate, err := parseDate(*due)
+						if err != nil {
+							return err
+						}
+						startDate, err := parseDate(*start)
+						if err != nil {
+							return err
+						}
+
+						updates := ProjectMetadata{
+							Status:    *status,
+							Priority:  *priority,
+							DueDate:   dueDate,
+							StartDate: startDate,
+							Area:      *area,
+						}
+						return updateProjects(ctx.Config, ctx.Args[0], updates, *tags)
+					}
+				},
+			},
+		},
+	}
+}
+
+func noteCommands() *Command {
+	return &Command{
+		Name:    "note",
+		Summary: "Create, list, link and rename notes",
+		Subcommands: []*Command{
+			{
+				Name:    "new",
+				Args:    "<title>",
+				Summary: "Create a new note",
+				Doing:   "creating note",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					title := fs.String("title", "", "Note title")
+					tags := fs.String("tags", "", "Comma-separated tags")
+					signature := fs.String("signature", "", "Signature for sequencing (e.g., 1a2)")
+					templateName := fs.String("template", "", "Template from ~/.config/notes-cli/templates/")
+					noEdit := fs.Bool("no-edit", false, "Skip opening editor")
+					return func(ctx *Context) error {
+						// Support positional argument for title
+						if *title == "" {
+							*title = strings.Join(ctx.Args, " ")
+						}
+						if *title == "" {
+							return errTitleRequired
+						}
+
+						noteTags := parseTags(*tags)
+						body, err := applyNoteTemplate(ctx.Config, *templateName, *title, &noteTags)
+						if err != nil {
+							return err
+						}
+						return createNote(ctx.Config, *title, noteTags, *signature, body, *noEdit)
+					}
+				},
+			},
+			{
+				Name:        "list",
+				Summary:     "List notes",
+				FlagValues:  map[string]string{"sort": "modified|created|title|signature"},
+				JSON:        true,
+				AllProfiles: true,
+				Doing:       "listing notes",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					tag := fs.String("tag", "", "Filter by tag")
+					sortBy := fs.String("sort", "modified", "Sort by: modified, created, title, signature")
+					reverse := fs.Bool("reverse", false, "Reverse sort order")
+					addWatchFlag(fs)
+					return func(ctx *Context) error {
+						filters := NoteFilters{
+							Tag:     *tag,
+							SortBy:  *sortBy,
+							Reverse: *reverse,
+						}
+						if ctx.JSON() {
+							return printNotesJSON(ctx.Config, filters)
+						}
+						if watchMode {
+							return watch(ctx.Config, func() error {
+								return listNotes(ctx.Config, filters)
+							})
+						}
+						if err := listNotes(ctx.Config, filters); err != nil {
+							return err
+						}
+						warnSyncConflicts(ctx.Config)
+						return nil
+					}
+				},
+			},
+			{
+				Name:     "edit",
+				Args:     "<note>",
+				Summary:  "Edit a note",
+				ArgKinds: []string{"note"},
+				MinArgs:  1,
+				Doing:    "editing note",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return editNote(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+			{
+				Name:     "rename",
+				Args:     "<note>",
+				Summary:  "Rename a note from its front matter",
+				ArgKinds: []string{"note"},
+				MinArgs:  1,
+				Doing:    "renaming note",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return renameNoteArg(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+			{
+				Name:     "link",
+				Args:     "<from> <to>",
+				Summary:  "Insert a denote: link to another note",
+				ArgKinds: []string{"note"},
+				MinArgs:  2,
+				Doing:    "linking notes",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return linkNotes(ctx.Config, ctx.Args[0], ctx.Args[1])
+					}
+				},
+			},
+			{
+				Name:     "backlinks",
+				Args:     "<note>",
+				Summary:  "List notes, tasks and projects linking here",
+				ArgKinds: []string{"note"},
+				MinArgs:  1,
+				Doing:    "listing backlinks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					return func(ctx *Context) error {
+						return listBacklinks(ctx.Config, ctx.Args[0])
+					}
+				},
+			},
+		},
+	}
+}
+
+func reportCommands() *Command {
+	return &Command{
+		Name:    "report",
+		Summary: "Generate reports (html, weekly)",
+		Subcommands: []*Command{
+			{
+				Name:       "html",
+				Summary:    "Write a static HTML status site",
+				FlagValues: map[string]string{"out": "dir"},
+				Doing:      "generating report",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					outDir := fs.String("out", "", "Directory to write the pages to")
+					title := fs.String("title", "Status", "Site title shown on every page")
+					return func(ctx *Context) error {
+						return generateHTMLReport(ctx.Config, HTMLReportOptions{OutDir: *outDir, Title: *title})
+					}
+				},
+			},
+			{
+				Name:       "weekly",
+				Summary:    "Summarize the week's finished, started and upcoming work",
+				FlagValues: map[string]string{"template": "file"},
+				Doing:      "generating report",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					since := fs.String("since", "7d", "Start of the window: 7d, 2w or YYYY-MM-DD")
+					groupBy := fs.String("group", "project", "Group the report by: project or area")
+					templateFile := fs.String("template", "", "Go template file (default: [report] weekly_template or the built-in one)")
+					output := fs.String("o", "", "Write to file instead of stdout")
+					return func(ctx *Context) error {
+						return weeklyReport(ctx.Config, WeeklyOptions{
+							Since:    *since,
+							GroupBy:  *groupBy,
+							Template: *templateFile,
+							Output:   *output,
+						})
+					}
+				},
+			},
+		},
+	}
+}
+
+func exportCommands() *Command {
+	return &Command{
+		Name:    "export",
+		Summary: "Export tasks to other formats (ics, taskwarrior, todotxt, org, csv)",
+		Subcommands: []*Command{
+			{
+				Name:    "ics",
+				Summary: "Export tasks and projects as an iCalendar file",
+				FlagValues: map[string]string{
+					"type":    "todo|event|both",
+					"include": "tasks|projects|both",
+				},
+				Doing: "exporting calendar",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					component := fs.String("type", "todo", "Calendar entries to emit: todo, event, or both")
+					include := fs.String("include", "tasks,projects", "What to export: tasks, projects, or both (comma separated)")
+					output := fs.String("o", "", "Write to file instead of stdout (can be published with 'serve -feed')")
+					return func(ctx *Context) error {
+						tasks, projects, err := parseICSInclude(*include)
+						if err != nil {
+							return err
+						}
+						return exportICS(ctx.Config, ICSOptions{
+							Filters:   filterFlags.filters(ctx.Config),
+							Tasks:     tasks,
+							Projects:  projects,
+							Component: *component,
+							Output:    *output,
+						})
+					}
+				},
+			},
+			{
+				Name:    "taskwarrior",
+				Summary: "Export tasks as Taskwarrior JSON",
+				Doing:   "exporting tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					output := fs.String("o", "", "Write to file instead of stdout")
+					return func(ctx *Context) error {
+						return exportTaskwarrior(ctx.Config, filterFlags.filters(ctx.Config), *output)
+					}
+				},
+			},
+			{
+				Name:    "todotxt",
+				Summary: "Export tasks as todo.txt",
+				Doing:   "exporting tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					output := fs.String("o", "", "Write to file instead of stdout")
+					return func(ctx *Context) error {
+						return exportTodoTxt(ctx.Config, filterFlags.filters(ctx.Config), *output)
+					}
+				},
+			},
+			{
+				Name:    "org",
+				Summary: "Export tasks as an Org mode outline",
+				Doing:   "exporting tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					groupBy := fs.String("group", "project", "Group headings by: project or area")
+					output := fs.String("o", "", "Write to file instead of stdout")
+					return func(ctx *Context) error {
+						return exportOrg(ctx.Config, filterFlags.filters(ctx.Config), *groupBy, *output)
+					}
+				},
+			},
+			{
+				Name:    "csv",
+				Summary: "Export tasks as CSV",
+				Doing:   "exporting tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					filterFlags := addTaskFilterFlags(fs)
+					columns := fs.String("columns", defaultCSVColumns, "Comma-separated columns: "+strings.Join(csvColumns, ", "))
+					output := fs.String("o", "", "Write to file instead of stdout")
+					return func(ctx *Context) error {
+						return exportCSV(ctx.Config, filterFlags.filters(ctx.Config), *columns, *output)
+					}
+				},
+			},
+		},
+	}
+}
+
+func importCommands() *Command {
+	return &Command{
+		Name:    "import",
+		Summary: "Import tasks from other tools (taskwarrior, todotxt, csv)",
+		Subcommands: []*Command{
+			{
+				Name:     "taskwarrior",
+				Args:     "<file.json|->",
+				Summary:  "Import tasks from 'task export' JSON",
+				ArgKinds: []string{"file"},
+				MinArgs:  1,
+				Doing:    "importing tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					dryRun := fs.Bool("dry-run", false, "Show what would be imported without creating tasks")
+					return func(ctx *Context) error {
+						return importTaskwarrior(ctx.Config, ctx.Args[0], *dryRun)
+					}
+				},
+			},
+			{
+				Name:     "todotxt",
+				Args:     "<todo.txt|->",
+				Summary:  "Import tasks from a todo.txt file",
+				ArgKinds: []string{"file"},
+				MinArgs:  1,
+				Doing:    "importing tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					dryRun := fs.Bool("dry-run", false, "Show what would be imported without creating tasks")
+					return func(ctx *Context) error {
+						return importTodoTxt(ctx.Config, ctx.Args[0], *dryRun)
+					}
+				},
+			},
+			{
+				Name:     "csv",
+				Args:     "<file.csv|->",
+				Summary:  "Import tasks from a CSV file",
+				ArgKinds: []string{"file"},
+				MinArgs:  1,
+				Doing:    "importing tasks",
+				Setup: func(fs *flag.FlagSet) func(ctx *Context) error {
+					dryRun := fs.Bool("dry-run", false, "Validate every row without creating tasks")
+					createProjects := fs.Bool("create-projects", false, "Create projects named in the project column that don't exist")
+					return func(ctx *Context) error {
+						return importCSV(ctx.Config, ctx.Args[0], CSVImportOptions{
+							DryRun:         *dryRun,
+							CreateProjects: *createProjects,
+						})
+					}
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,575 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// flagCompletions are the values flags take wherever they appear, unless a
+// command's FlagValues says otherwise
+var flagCompletions = map[string]string{
+	"p":        "p1|p2|p3",
+	"status":   "open|paused|delegated|done|dropped",
+	"sort":     "modified|priority|due|created|start|estimate",
+	"estimate": "1|2|3|5|8|13",
+	"project":  "project-name",
+	"area":     "area",
+	"tag":      "tag",
+	"tags":     "tag",
+	"template": "template",
+	"group":    "project|area",
+	"keep":     "original|conflict|merge",
+	"prefer":   "local|remote",
+	"o":        "file",
+	"feed":     "file",
+	"dir":      "dir",
+	"format":   "text|json",
+	"profile":  "profile",
+}
+
+// completionItem is one candidate: the value and what it is
+type completionItem struct {
+	value       string
+	description string
+}
+
+// completer works out completions for a partial command line. The vault is
+// only read when a live value (a task, a tag...) is needed.
+type completer struct {
+	config *Config
+}
+
+func (c *completer) loadConfig() Config {
+	if c.config == nil {
+		config := loadConfig()
+		c.config = &config
+	}
+	return *c.config
+}
+
+// completeCommand implements '__complete <n> <words...>': print the
+// completions for word n (counting from 0 after "notes-cli"), one per line
+// as "value<TAB>description", then a line ":files", ":dirs" or ":" asking
+// the shell for file names or nothing more.
+func completeCommand(ctx *Context) error {
+	if len(ctx.Args) == 0 {
+		return fmt.Errorf("usage: notes-cli __complete <n> [words]")
+	}
+	n, err := strconv.Atoi(ctx.Args[0])
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid word index: %s", ctx.Args[0])
+	}
+
+	words := ctx.Args[1:]
+	cur := ""
+	if n < len(words) {
+		cur = words[n]
+	} else {
+		n = len(words)
+	}
+
+	c := &completer{}
+	items, directive := c.complete(words[:n], cur)
+	for _, item := range items {
+		if item.description != "" && item.description != item.value {
+			fmt.Printf("%s\t%s\n", item.value, item.description)
+		} else {
+			fmt.Println(item.value)
+		}
+	}
+	fmt.Println(":" + directive)
+	return nil
+}
+
+// complete returns the candidates for cur, given the words before it
+func (c *completer) complete(words []string, cur string) ([]completionItem, string) {
+	var cmd *Command
+	var path, positional []string
+	fs := flag.NewFlagSet("notes-cli", flag.ContinueOnError)
+	addGlobalFlags(fs)
+
+	var pending *flag.Flag
+	for i, word := range words {
+		if pending != nil {
+			if isGlobalFlag(pending.Name) {
+				fs.Set(pending.Name, word)
+			}
+			pending = nil
+			continue
+		}
+
+		if len(word) > 1 && word[0] == '-' && word != "--" {
+			name, value, hasValue := strings.Cut(strings.TrimLeft(word, "-"), "=")
+			f := fs.Lookup(name)
+			switch {
+			case f == nil:
+			case hasValue:
+				if isGlobalFlag(name) {
+					fs.Set(name, value)
+				}
+			case takesValue(f):
+				pending = f
+			}
+			continue
+		}
+
+		// Command names come first, then arguments
+		if len(positional) == 0 {
+			var sub *Command
+			if cmd == nil {
+				if alias, ok := commandAliases[word]; ok {
+					var rest []string
+					sub, path, rest = lookupCommand(alias)
+					if len(rest) > 0 {
+						sub = nil
+					}
+				} else if sub = findCommand(commands, word); sub != nil {
+					path = []string{word}
+				}
+			} else if sub = findCommand(cmd.Subcommands, word); sub != nil {
+				path = append(path, word)
+			}
+			if sub != nil {
+				cmd = sub
+				fs, _ = commandFlags(cmd, path)
+				continue
+			}
+			if cmd == nil {
+				// An unknown command: nothing to offer
+				return nil, ""
+			}
+		}
+		positional = append(positional, words[i])
+	}
+
+	if pending != nil {
+		return c.values(flagValueKind(cmd, pending.Name), cur)
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		if name, value, ok := strings.Cut(strings.TrimLeft(cur, "-"), "="); ok {
+			prefix := cur[:len(cur)-len(value)]
+			items, directive := c.values(flagValueKind(cmd, name), value)
+			for i := range items {
+				items[i].value = prefix + items[i].value
+			}
+			return items, directive
+		}
+		return flagItems(fs, cur), ""
+	}
+
+	var items []completionItem
+	switch {
+	case cmd == nil:
+		items = commandItems(commands, cur)
+	case cmd.Name == "help" && len(path) == 1:
+		// 'help' takes a command path
+		group, _, rest := lookupCommand(positional)
+		if len(positional) == 0 {
+			items = commandItems(commands, cur)
+		} else if group != nil && len(rest) == 0 {
+			items = commandItems(group.Subcommands, cur)
+		}
+		return items, ""
+	}
+
+	if cmd == nil {
+		return items, ""
+	}
+	if len(positional) == 0 {
+		items = append(items, commandItems(cmd.Subcommands, cur)...)
+	}
+	if cmd.Setup == nil || len(cmd.ArgKinds) == 0 {
+		return items, ""
+	}
+
+	kind := cmd.ArgKinds[len(cmd.ArgKinds)-1]
+	if len(positional) < len(cmd.ArgKinds) {
+		kind = cmd.ArgKinds[len(positional)]
+	}
+	values, directive := c.values(kind, cur)
+	return append(items, values...), directive
+}
+
+// takesValue reports whether a flag needs the next word as its value
+func takesValue(f *flag.Flag) bool {
+	if _, ok := f.Value.(optionalValue); ok {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !b.IsBoolFlag()
+}
+
+func flagValueKind(cmd *Command, name string) string {
+	if cmd != nil {
+		if kind, ok := cmd.FlagValues[name]; ok {
+			return kind
+		}
+	}
+	return flagCompletions[name]
+}
+
+func commandItems(list []*Command, cur string) []completionItem {
+	var items []completionItem
+	for _, cmd := range list {
+		if !cmd.Hidden && strings.HasPrefix(cmd.Name, cur) {
+			items = append(items, completionItem{cmd.Name, cmd.Summary})
+		}
+	}
+	return items
+}
+
+func flagItems(fs *flag.FlagSet, cur string) []completionItem {
+	var items []completionItem
+	fs.VisitAll(func(f *flag.Flag) {
+		if name := "-" + f.Name; strings.HasPrefix(name, cur) {
+			_, usage := flag.UnquoteUsage(f)
+			items = append(items, completionItem{name, usage})
+		}
+	})
+	return items
+}
+
+// values completes a value of the given kind
+func (c *completer) values(kind, cur string) ([]completionItem, string) {
+	switch kind {
+	case "":
+		return nil, ""
+	case "file":
+		return nil, "files"
+	case "dir":
+		return nil, "dirs"
+	}
+
+	var items []completionItem
+	if strings.Contains(kind, "|") {
+		for _, choice := range strings.Split(kind, "|") {
+			items = append(items, completionItem{value: choice})
+		}
+		return filterItems(items, cur), ""
+	}
+
+	// Tags are given as lists: complete the last one, keeping any "-"
+	prefix := ""
+	if kind == "tag" {
+		if i := strings.LastIndex(cur, ","); i >= 0 {
+			prefix, cur = cur[:i+1], cur[i+1:]
+		}
+		if strings.HasPrefix(cur, "-") {
+			prefix, cur = prefix+"-", cur[1:]
+		}
+	}
+
+	switch kind {
+	case "task":
+		items = c.taskItems()
+	case "project":
+		items = c.projectItems(false)
+	case "project-name":
+		items = c.projectItems(true)
+	case "note":
+		items = c.noteItems()
+	case "tag":
+		items = c.tagItems()
+	case "area":
+		items = c.areaItems()
+	case "conflict":
+		items = c.conflictItems()
+	case "template":
+		items = templateItems()
+	case "profile":
+		if tomlConfig, err := loadTOMLConfig(); err == nil {
+			for _, name := range profileNames(tomlConfig) {
+				items = append(items, completionItem{value: name})
+			}
+		}
+	}
+
+	items = filterItems(items, cur)
+	for i := range items {
+		items[i].value = prefix + items[i].value
+	}
+	return items, ""
+}
+
+func filterItems(items []completionItem, cur string) []completionItem {
+	var result []completionItem
+	for _, item := range items {
+		if strings.HasPrefix(item.value, cur) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// taskItems are task IDs, open tasks first
+func (c *completer) taskItems() []completionItem {
+	tasks, _ := findTasks(c.loadConfig(), TaskFilters{All: true, SortBy: "priority"})
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return (tasks[i].Status == "open") && tasks[j].Status != "open"
+	})
+
+	var items []completionItem
+	for _, task := range tasks {
+		if task.TaskID == 0 {
+			continue
+		}
+		description := task.Note.Title
+		if task.Status != "open" {
+			description += " (" + task.Status + ")"
+		}
+		items = append(items, completionItem{strconv.Itoa(task.TaskID), description})
+	}
+	return items
+}
+
+// projectItems are project IDs, or with names the titles the -project
+// flag takes
+func (c *completer) projectItems(names bool) []completionItem {
+	projects, _ := findProjects(c.loadConfig(), ProjectFilters{All: true, SortBy: "name"})
+
+	var items []completionItem
+	for _, project := range projects {
+		switch {
+		case names:
+			items = append(items, completionItem{project.Note.Title, project.Status})
+		case project.ProjectID > 0:
+			items = append(items, completionItem{strconv.Itoa(project.ProjectID), project.Note.Title})
+		}
+	}
+	return items
+}
+
+func (c *completer) noteItems() []completionItem {
+	notes, _ := findNotes(c.loadConfig(), NoteFilters{SortBy: "modified"})
+
+	var items []completionItem
+	for _, note := range notes {
+		items = append(items, completionItem{note.Filename, note.Note.Title})
+	}
+	return items
+}
+
+// tagItems are the tags in use, read from file names
+func (c *completer) tagItems() []completionItem {
+	config := c.loadConfig()
+	dirs := []string{config.NotesDir}
+	if config.TaskDir != config.NotesDir {
+		dirs = append(dirs, config.TaskDir)
+	}
+
+	counts := make(map[string]int)
+	for _, dir := range dirs {
+		files, _ := globNotes(filepath.Join(dir, "*.md"))
+		for _, file := range files {
+			note, err := parseFilename(filepath.Base(file))
+			if err != nil {
+				continue
+			}
+			for _, tag := range note.Tags {
+				if tag != "task" && tag != "project" {
+					counts[tag]++
+				}
+			}
+		}
+	}
+	return countedItems(counts, "file", "files")
+}
+
+// areaItems are the areas tasks and projects use
+func (c *completer) areaItems() []completionItem {
+	config := c.loadConfig()
+	counts := make(map[string]int)
+
+	tasks, _ := findTasks(config, TaskFilters{All: true})
+	for _, task := range tasks {
+		if task.Area != "" {
+			counts[task.Area]++
+		}
+	}
+	projects, _ := findProjects(config, ProjectFilters{All: true})
+	for _, project := range projects {
+		if project.Area != "" {
+			counts[project.Area]++
+		}
+	}
+	for _, area := range config.TOMLConfig.Vocabulary.Areas {
+		if _, ok := counts[area]; !ok {
+			counts[area] = 0
+		}
+	}
+	return countedItems(counts, "use", "uses")
+}
+
+func countedItems(counts map[string]int, singular, plural string) []completionItem {
+	var names []string
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var items []completionItem
+	for _, name := range names {
+		items = append(items, completionItem{name, fmt.Sprintf("%d %s", counts[name], pluralize(counts[name], singular, plural))})
+	}
+	return items
+}
+
+func (c *completer) conflictItems() []completionItem {
+	conflicts, _ := findSyncConflicts(c.loadConfig())
+
+	var items []completionItem
+	for _, conflict := range conflicts {
+		items = append(items, completionItem{strconv.Itoa(conflict.Index), filepath.Base(conflict.Path)})
+	}
+	return items
+}
+
+func templateItems() []completionItem {
+	var items []completionItem
+	files, _ := filepath.Glob(filepath.Join(templatesDir(), "*.md"))
+	for _, file := range files {
+		items = append(items, completionItem{value: strings.TrimSuffix(filepath.Base(file), ".md")})
+	}
+	return items
+}
+
+// printCompletionScript writes the completion script for a shell. The
+// scripts ask 'notes-cli __complete' for candidates, so they follow the
+// command definitions and the vault.
+func printCompletionScript(shell string) error {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unknown shell %q (use bash, zsh, fish or powershell)", shell)
+	}
+	_, err := fmt.Fprint(os.Stdout, script)
+	return err
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for notes-cli
+# Generated by 'notes-cli completion bash'. Load it with:
+#   source <(notes-cli completion bash)
+
+_notes_cli() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local -a lines
+    mapfile -t lines < <(notes-cli __complete "$((COMP_CWORD - 1))" "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+
+    local n=${#lines[@]}
+    [[ $n -gt 0 ]] || return
+    local directive="${lines[n-1]}"
+
+    COMPREPLY=()
+    local line
+    for line in "${lines[@]:0:n-1}"; do
+        COMPREPLY+=("$(printf '%q' "${line%%$'\t'*}")")
+    done
+
+    case "$directive" in
+        :files)
+            compopt -o filenames
+            mapfile -t -O "${#COMPREPLY[@]}" COMPREPLY < <(compgen -f -- "$cur")
+            ;;
+        :dirs)
+            compopt -o filenames
+            mapfile -t -O "${#COMPREPLY[@]}" COMPREPLY < <(compgen -d -- "$cur")
+            ;;
+    esac
+}
+
+complete -F _notes_cli notes-cli
+`,
+
+	"zsh": `#compdef notes-cli
+# zsh completion for notes-cli
+# Generated by 'notes-cli completion zsh'. Put it in your $fpath as
+# _notes-cli, or load it with:
+#   source <(notes-cli completion zsh)
+
+_notes_cli() {
+    local -a lines values
+    local line directive
+
+    lines=("${(@f)$(notes-cli __complete $((CURRENT - 2)) "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    (( ${#lines} )) || return 1
+    directive=${lines[-1]}
+
+    for line in "${(@)lines[1,-2]}"; do
+        [[ -n $line ]] || continue
+        if [[ $line == *$'\t'* ]]; then
+            values+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            values+=("${line//:/\\:}")
+        fi
+    done
+
+    (( ${#values} )) && _describe -t values 'notes-cli' values
+    case $directive in
+        :files) _files ;;
+        :dirs) _files -/ ;;
+    esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+    _notes_cli "$@"
+else
+    compdef _notes_cli notes-cli
+fi
+`,
+
+	"fish": `# fish completion for notes-cli
+# Generated by 'notes-cli completion fish'. Save it as
+# ~/.config/fish/completions/notes-cli.fish, or load it with:
+#   notes-cli completion fish | source
+
+function __notes_cli_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    set -l cur (commandline -ct)
+    set -l lines (notes-cli __complete (count $args) $args "$cur" 2>/dev/null)
+    test (count $lines) -gt 0; or return
+
+    set -l directive $lines[-1]
+    set -e lines[-1]
+    printf '%s\n' $lines
+    switch $directive
+        case :files
+            __fish_complete_path "$cur"
+        case :dirs
+            __fish_complete_directories "$cur"
+    end
+end
+
+complete -c notes-cli -f -a '(__notes_cli_complete)'
+`,
+
+	"powershell": `# PowerShell completion for notes-cli
+# Generated by 'notes-cli completion powershell'. Load it from your profile:
+#   notes-cli completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName notes-cli -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        ForEach-Object { $_.ToString() })
+    $lines = @(& notes-cli __complete $words.Count @words $wordToComplete 2>$null)
+    if ($lines.Count -eq 0) { return }
+
+    # Returning nothing for :files lets PowerShell complete paths
+    $lines | Select-Object -SkipLast 1 | ForEach-Object {
+        $value, $description = $_ -split "` + "`" + `t", 2
+        if (-not $description) { $description = $value }
+        $text = $value
+        if ($value -match '\s') { $text = "'" + $value.Replace("'", "''") + "'" }
+        [System.Management.Automation.CompletionResult]::new($text, $value, 'ParameterValue', $description)
+    }
+}
+`,
+}
@@ -0,0 +1,24 @@
+# fish completion for notes-cli
+# Generated by 'notes-cli completion fish'. Save it as
+# ~/.config/fish/completions/notes-cli.fish, or load it with:
+#   notes-cli completion fish | source
+
+function __notes_cli_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    set -l cur (commandline -ct)
+    set -l lines (notes-cli __complete (count $args) $args "$cur" 2>/dev/null)
+    test (count $lines) -gt 0; or return
+
+    set -l directive $lines[-1]
+    set -e lines[-1]
+    printf '%s\n' $lines
+    switch $directive
+        case :files
+            __fish_complete_path "$cur"
+        case :dirs
+            __fish_complete_directories "$cur"
+    end
+end
+
+complete -c notes-cli -f -a '(__notes_cli_complete)'
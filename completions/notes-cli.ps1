@@ -0,0 +1,22 @@
+# PowerShell completion for notes-cli
+# Generated by 'notes-cli completion powershell'. Load it from your profile:
+#   notes-cli completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName notes-cli -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        ForEach-Object { $_.ToString() })
+    $lines = @(& notes-cli __complete $words.Count @words $wordToComplete 2>$null)
+    if ($lines.Count -eq 0) { return }
+
+    # Returning nothing for :files lets PowerShell complete paths
+    $lines | Select-Object -SkipLast 1 | ForEach-Object {
+        $value, $description = $_ -split "`t", 2
+        if (-not $description) { $description = $value }
+        $text = $value
+        if ($value -match '\s') { $text = "'" + $value.Replace("'", "''") + "'" }
+        [System.Management.Automation.CompletionResult]::new($text, $value, 'ParameterValue', $description)
+    }
+}
@@ -1,15 +1,97 @@
 package main
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/BurntSushi/toml"
 )
 
 type TOMLConfig struct {
-	SoonHorizon int    `toml:"soon_horizon"`
-	NotesDir    string `toml:"notes_dir"`
-	TaskDir     string `toml:"task_dir"`
+	SoonHorizon   int               `toml:"soon_horizon"`
+	NotesDir      string            `toml:"notes_dir"`
+	TaskDir       string            `toml:"task_dir"`
+	FileNameOrder []string          `toml:"file_name_order"`
+	Journal       JournalConfig     `toml:"journal"`
+	Templates     TemplatesConfig   `toml:"templates"`
+	CalDAV        CalDAVConfig      `toml:"caldav"`
+	Report        ReportConfig      `toml:"report"`
+	Git           GitConfig         `toml:"git"`
+	Hooks         map[string]string `toml:"hooks"`
+	Remind        RemindConfig      `toml:"remind"`
+
+	Vocabulary     VocabularyConfig         `toml:"vocabulary"`
+	DefaultProfile string                   `toml:"default_profile"`
+	Profiles       map[string]ProfileConfig `toml:"profiles"`
+
+	sources   map[string]string // key -> file that set it
+	localFile string            // the .notes-cli.toml in use, if any
+}
+
+type JournalConfig struct {
+	TitleFormat   string `toml:"title_format"`
+	TasksTemplate string `toml:"tasks_template"`
+}
+
+// TemplatesConfig names the default template for each type
+type TemplatesConfig struct {
+	Note    string `toml:"note"`
+	Task    string `toml:"task"`
+	Project string `toml:"project"`
+}
+
+// ReportConfig customizes 'report weekly'
+type ReportConfig struct {
+	WeeklyTemplate string `toml:"weekly_template"`
+}
+
+// CalDAVConfig is the task collection used by 'sync caldav'
+type CalDAVConfig struct {
+	URL             string `toml:"url"`
+	Username        string `toml:"username"`
+	Password        string `toml:"password"`
+	PasswordCommand string `toml:"password_command"`
+}
+
+// RemindConfig is how 'remind' delivers reminders
+type RemindConfig struct {
+	Time      string   `toml:"time"`
+	Notifiers []string `toml:"notifiers"`
+	Webhook   string   `toml:"webhook"`
+	Exec      string   `toml:"exec"`
+	Interval  string   `toml:"interval"`
+}
+
+// localConfigName is the per-directory config file, found by walking up
+// from the working directory and merged over the user config
+const localConfigName = ".notes-cli.toml"
+
+func userConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "notes-cli", "config.toml")
+}
+
+// findLocalConfig returns the nearest .notes-cli.toml in dir or its parents
+func findLocalConfig(dir string) string {
+	for {
+		path := filepath.Join(dir, localConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
 }
 
 func loadTOMLConfig() (*TOMLConfig, error) {
@@ -17,31 +99,193 @@ func loadTOMLConfig() (*TOMLConfig, error) {
 		SoonHorizon: 7,  // Default to 7 days
 		NotesDir:    "", // Empty means use env var or default
 		TaskDir:     "", // Empty means use notes_dir
+		Journal: JournalConfig{
+			TitleFormat: defaultJournalTitleFormat,
+		},
+		Remind: RemindConfig{
+			Time:      defaultRemindTime,
+			Notifiers: []string{"stdout"},
+			Interval:  "1m",
+		},
+		sources: make(map[string]string),
 	}
 	
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return config, nil // Return default config
+	if path := userConfigPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if err := decodeConfigFile(path, config); err != nil {
+				return config, err
+			}
+		}
+	}
+	
+	if cwd, err := os.Getwd(); err == nil {
+		if path := findLocalConfig(cwd); path != "" {
+			if err := decodeConfigFile(path, config); err != nil {
+				return config, err
+			}
+			config.localFile = path
+		}
 	}
 	
-	configPath := filepath.Join(home, ".config", "notes-cli", "config.toml")
+	if err := validateTOMLConfig(config); err != nil {
+		return config, err
+	}
 	
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		// Create default config file
-		err = createDefaultConfig(configPath)
-		if err != nil {
-			return config, nil // Return default config if we can't create file
+	return config, nil
+}
+
+// localConfigKeys are the top-level keys a .notes-cli.toml may set. Such
+// files come with directories you clone or unpack, so they can choose where
+// files go and how they're named and tagged, but not hooks, reminder
+// commands, CalDAV or git settings, which run commands or send data
+// elsewhere.
+var localConfigKeys = []string{
+	"soon_horizon", "notes_dir", "task_dir", "file_name_order", "journal",
+	"templates", "report", "vocabulary", "default_profile", "profiles",
+}
+
+// checkLocalConfigKeys rejects a .notes-cli.toml that sets keys outside
+// localConfigKeys, before any of it is merged
+func checkLocalConfigKeys(path string) error {
+	var raw map[string]interface{}
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	
+	var refused []string
+	for key := range raw {
+		if !containsTag(localConfigKeys, key) {
+			refused = append(refused, key)
+		}
+	}
+	if len(refused) > 0 {
+		sort.Strings(refused)
+		return fmt.Errorf("%s: %s can only be set in %s (local config files may set %s)",
+			path, strings.Join(refused, ", "), userConfigPath(), strings.Join(localConfigKeys, ", "))
+	}
+	return nil
+}
+
+// decodeConfigFile merges a config file over config, recording which keys
+// it set
+func decodeConfigFile(path string, config *TOMLConfig) error {
+	if filepath.Base(path) == localConfigName {
+		if err := checkLocalConfigKeys(path); err != nil {
+			return err
 		}
 	}
 	
-	// Read config file
-	_, err = toml.DecodeFile(configPath, config)
+	md, err := toml.DecodeFile(path, config)
 	if err != nil {
-		return config, nil // Return default config on error
+		return fmt.Errorf("%s: %w", path, err)
 	}
 	
-	return config, nil
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var keys []string
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return fmt.Errorf("%s: unknown %s %s", path, pluralize(len(keys), "key", "keys"), strings.Join(keys, ", "))
+	}
+	
+	for _, key := range md.Keys() {
+		config.sources[key.String()] = path
+	}
+	
+	// Relative directories in a local config are relative to the file, so
+	// a repository can carry its own tasks directory
+	if filepath.Base(path) == localConfigName {
+		dir := filepath.Dir(path)
+		resolve := func(value *string, key string) {
+			if config.sources[key] == path && *value != "" && !strings.HasPrefix(*value, "~") && !filepath.IsAbs(*value) {
+				*value = filepath.Join(dir, *value)
+			}
+		}
+		resolve(&config.NotesDir, "notes_dir")
+		resolve(&config.TaskDir, "task_dir")
+		for name, profile := range config.Profiles {
+			resolve(&profile.NotesDir, "profiles."+name+".notes_dir")
+			resolve(&profile.TaskDir, "profiles."+name+".task_dir")
+			config.Profiles[name] = profile
+		}
+	}
+	
+	return nil
+}
+
+// validateTOMLConfig checks values the TOML decoder can't, naming the file
+// each bad value came from
+func validateTOMLConfig(config *TOMLConfig) error {
+	var problems []string
+	problem := func(key, format string, args ...interface{}) {
+		source := config.sources[key]
+		if source == "" {
+			source = "config"
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s: %s", source, key, fmt.Sprintf(format, args...)))
+	}
+	
+	if config.SoonHorizon < 0 {
+		problem("soon_horizon", "must not be negative")
+	}
+	if _, err := normalizeFilenameOrder(config.FileNameOrder); err != nil {
+		problem("file_name_order", "%v", err)
+	}
+	if config.CalDAV.URL != "" {
+		if u, err := url.Parse(config.CalDAV.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			problem("caldav.url", "must be an http or https URL")
+		}
+	}
+	
+	if _, err := time.Parse("15:04", config.Remind.Time); err != nil {
+		problem("remind.time", "must be a time like 09:00")
+	}
+	for _, name := range config.Remind.Notifiers {
+		if !isNotifier(name) {
+			problem("remind.notifiers", "unknown notifier %q (use %s)", name, strings.Join(notifierNames, ", "))
+		}
+	}
+	if containsTag(config.Remind.Notifiers, "webhook") && config.Remind.Webhook == "" {
+		problem("remind.webhook", "required by the webhook notifier")
+	}
+	if containsTag(config.Remind.Notifiers, "exec") && config.Remind.Exec == "" {
+		problem("remind.exec", "required by the exec notifier")
+	}
+	if config.Remind.Webhook != "" {
+		if u, err := url.Parse(config.Remind.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			problem("remind.webhook", "must be an http or https URL")
+		}
+	}
+	if interval, err := time.ParseDuration(config.Remind.Interval); err != nil || interval <= 0 {
+		problem("remind.interval", "must be a duration like 1m")
+	}
+	
+	for event := range config.Hooks {
+		if !isHookEvent(event) {
+			problem("hooks."+event, "unknown event (use pre- or post- create, update, done, delete or log)")
+		}
+	}
+	
+	if config.DefaultProfile != "" {
+		if _, ok := config.Profiles[config.DefaultProfile]; !ok {
+			problem("default_profile", "no profile named %q", config.DefaultProfile)
+		}
+	}
+	for _, name := range profileNames(config) {
+		profile := config.Profiles[name]
+		if profile.NotesDir == "" {
+			problem("profiles."+name, "notes_dir is required")
+		}
+		if profile.SoonHorizon < 0 {
+			problem("profiles."+name+".soon_horizon", "must not be negative")
+		}
+	}
+	
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
 }
 
 func createDefaultConfig(configPath string) error {
@@ -63,6 +307,77 @@ soon_horizon = 7
 # task_dir - where to store tasks (default: same as notes_dir)
 notes_dir = ""
 task_dir = ""
+
+# Order of Denote file name components (default shown)
+# file_name_order = ["identifier", "signature", "title", "keywords"]
+
+# Profile used when neither -profile nor $NOTES_PROFILE is given
+# default_profile = "work"
+
+# Allowed areas and tags for tasks and projects (empty allows anything)
+[vocabulary]
+# areas = ["work", "personal"]
+# tags = []
+
+# Separate vaults, selected with -profile or $NOTES_PROFILE. Each profile
+# has its own notes_dir and optionally task_dir, soon_horizon and vocabulary.
+# [profiles.work]
+# notes_dir = "~/work/notes"
+# soon_horizon = 3
+# [profiles.work.vocabulary]
+# areas = ["eng", "ops"]
+#
+# [profiles.personal]
+# notes_dir = "~/notes"
+
+# Daily journal notes
+[journal]
+# Title for journal notes, as a Go time layout
+title_format = "Monday 2 January 2006"
+# Go template for the section added by 'journal -tasks' (empty for the built-in one)
+# tasks_template = ""
+
+# Default templates from ~/.config/notes-cli/templates/ (override with -template)
+[templates]
+# note = "meeting"
+# task = "bug"
+# project = "charter"
+
+# Two-way task sync with a CalDAV VTODO collection ('sync caldav')
+[caldav]
+# url = "https://dav.example.com/calendars/me/tasks/"
+# username = "me"
+# Command printing the password, so it needn't be stored here
+# password_command = "pass show caldav"
+
+# Reports
+[report]
+# Go template for 'report weekly' (empty for the built-in one)
+# weekly_template = ""
+
+# Commit task changes when the vault is a git repository
+[git]
+# Commit each change made by notes-cli, e.g. "task #42: status open → done"
+auto_commit = false
+
+# Commands run on task and project events, with the task as JSON on stdin.
+# Executables in ~/.config/notes-cli/hooks/ named after the event work too.
+# Events: pre-/post- create, update, done, delete, log. A failing pre- hook
+# stops the change.
+[hooks]
+# post-create = "jq -e '.new.priority == \"p1\"' >/dev/null && notify-send 'New p1 task'"
+
+# Reminders for tasks with a due date or a remind field ('remind')
+[remind]
+# Time of day a due date means
+time = "09:00"
+# Where reminders go: stdout, notify-send, webhook, exec
+notifiers = ["stdout"]
+# webhook = "https://ntfy.sh/my-tasks"
+# Command run for each reminder, with the reminder as JSON on stdin
+# exec = "terminal-notifier -title notes-cli -message \"$NOTES_CLI_MESSAGE\""
+# How often 'remind -daemon' checks
+interval = "1m"
 `
 	
 	return os.WriteFile(configPath, []byte(defaultConfig), 0644)
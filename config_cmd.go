@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configEntry is one key of the effective config for 'config show'
+type configEntry struct {
+	key    string
+	value  string
+	source string
+}
+
+// showConfig prints the effective config as TOML, noting where each value
+// came from
+func showConfig(config Config) error {
+	tc := config.TOMLConfig
+
+	fmt.Println(dim("# Config files, later ones override earlier ones:"))
+	if path := userConfigPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			fmt.Println(dim("#   " + path))
+		} else {
+			fmt.Println(dim("#   " + path + " (not found; create it with 'notes-cli config init')"))
+		}
+	}
+	if tc.localFile != "" {
+		fmt.Println(dim("#   " + tc.localFile))
+	}
+	if config.Profile != "" {
+		fmt.Println(dim("# Profile: " + config.Profile))
+	}
+	fmt.Println()
+
+	source := func(key string) string {
+		if s, ok := tc.sources[key]; ok {
+			return s
+		}
+		return "default"
+	}
+
+	// The directories actually used, after $NOTES_DIR and defaults
+	notesSource := source("notes_dir")
+	if tc.NotesDir == "" {
+		notesSource = "default"
+		if os.Getenv("NOTES_DIR") != "" {
+			notesSource = "$NOTES_DIR"
+		}
+	}
+	taskSource := source("task_dir")
+	if tc.TaskDir == "" {
+		taskSource = "same as notes_dir"
+	}
+
+	var top []configEntry
+	var sections []string
+	tables := make(map[string][]configEntry)
+
+	v := reflect.ValueOf(*tc)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("toml")
+		if key == "" {
+			continue
+		}
+		field := v.Field(i)
+
+		switch {
+		case key == "notes_dir":
+			top = append(top, configEntry{key, strconv.Quote(config.NotesDir), notesSource})
+		case key == "task_dir":
+			top = append(top, configEntry{key, strconv.Quote(config.TaskDir), taskSource})
+		case field.Kind() == reflect.Struct:
+			sections = append(sections, key)
+			tables[key] = structEntries(field, key, source)
+		case field.Kind() == reflect.Map && key == "profiles":
+			for _, name := range profileNames(tc) {
+				profile := reflect.ValueOf(tc.Profiles[name])
+				section := "profiles." + name
+				var entries []configEntry
+				for _, entry := range structEntries(profile, section, source) {
+					if strings.HasPrefix(entry.key, "vocabulary.") {
+						tables[section+".vocabulary"] = append(tables[section+".vocabulary"],
+							configEntry{strings.TrimPrefix(entry.key, "vocabulary."), entry.value, entry.source})
+						continue
+					}
+					entries = append(entries, entry)
+				}
+				sections = append(sections, section)
+				tables[section] = entries
+				if len(tables[section+".vocabulary"]) > 0 {
+					sections = append(sections, section+".vocabulary")
+				}
+			}
+		case field.Kind() == reflect.Map:
+			var names []string
+			for _, k := range field.MapKeys() {
+				names = append(names, k.String())
+			}
+			sort.Strings(names)
+			var entries []configEntry
+			for _, name := range names {
+				entries = append(entries, configEntry{name, formatConfigValue(name, field.MapIndex(reflect.ValueOf(name))), source(key + "." + name)})
+			}
+			sections = append(sections, key)
+			tables[key] = entries
+		default:
+			top = append(top, configEntry{key, formatConfigValue(key, field), source(key)})
+		}
+	}
+
+	printConfigEntries(top)
+	for _, section := range sections {
+		if len(tables[section]) == 0 {
+			continue
+		}
+		fmt.Printf("\n[%s]\n", section)
+		printConfigEntries(tables[section])
+	}
+
+	return nil
+}
+
+// structEntries lists a config section's keys. Nested tables come back with
+// dotted keys.
+func structEntries(v reflect.Value, prefix string, source func(string) string) []configEntry {
+	var entries []configEntry
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("toml")
+		if key == "" {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			for _, entry := range structEntries(field, prefix+"."+key, source) {
+				entry.key = key + "." + entry.key
+				entries = append(entries, entry)
+			}
+			continue
+		}
+		entries = append(entries, configEntry{key, formatConfigValue(key, field), source(prefix + "." + key)})
+	}
+	return entries
+}
+
+// formatConfigValue writes a value as TOML, hiding passwords
+func formatConfigValue(key string, v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		if key == "password" && v.String() != "" {
+			return `"********"`
+		}
+		return strconv.Quote(v.String())
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Slice:
+		var items []string
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, formatConfigValue(key, v.Index(i)))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func printConfigEntries(entries []configEntry) {
+	width := 0
+	for _, entry := range entries {
+		if n := len(entry.key) + len(entry.value) + 3; n > width {
+			width = n
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	for _, entry := range entries {
+		line := entry.key + " = " + entry.value
+		source := entry.source
+		if home != "" {
+			source = strings.ReplaceAll(source, home+string(filepath.Separator), "~/")
+		}
+		fmt.Printf("%-*s  %s\n", width, line, dim("# "+source))
+	}
+}
+
+// initConfig writes a commented default config: the user config, or with
+// local a .notes-cli.toml in the current directory
+func initConfig(local bool) error {
+	path := userConfigPath()
+	if local {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		path = filepath.Join(cwd, localConfigName)
+	}
+	if path == "" {
+		return fmt.Errorf("cannot locate the config directory")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	var err error
+	if local {
+		err = os.WriteFile(path, []byte(defaultLocalConfig), 0644)
+	} else {
+		err = createDefaultConfig(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("%s Created %s\n", success("✓"), path)
+	return nil
+}
+
+const defaultLocalConfig = `# notes-cli settings for this directory and the ones below it, merged
+# over ~/.config/notes-cli/config.toml. Relative paths are relative to
+# this file. Hooks, reminders, CalDAV and git can only be set in the user
+# config.
+
+# task_dir = "tasks"
+`
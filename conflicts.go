@@ -0,0 +1,448 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File sync tools keep both versions when a file changes on two machines:
+//
+//	Syncthing  note.sync-conflict-20250704-151739-ABCDEFG.md
+//	Dropbox    note (conflicted copy 2025-07-04).md, note (Ann's conflicted copy 2025-07-04).md
+//	iCloud     note 2.md
+//
+// The copies have the same Denote identifier and task_id as the original,
+// so discovery skips them and 'conflicts' resolves them.
+var (
+	syncthingConflictPattern = regexp.MustCompile(`\.sync-conflict-\d{8}-\d{6}(?:-[A-Za-z0-9]+)?`)
+	dropboxConflictPattern   = regexp.MustCompile(`(?i) \([^)]*conflicted copy[^)]*\)`)
+	numberedCopyPattern      = regexp.MustCompile(` \(?\d+\)?$`)
+)
+
+// conflictOriginalName returns the file name a conflict copy was made from
+func conflictOriginalName(name string) (string, bool) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	if loc := syncthingConflictPattern.FindStringIndex(stem); loc != nil {
+		return stem[:loc[0]] + stem[loc[1]:] + ext, true
+	}
+	if loc := dropboxConflictPattern.FindStringIndex(stem); loc != nil {
+		return stem[:loc[0]] + stem[loc[1]:] + ext, true
+	}
+	return "", false
+}
+
+// isSyncConflict reports whether path is a copy left by a sync tool.
+// Numbered copies ("note 2.md") only count if the original exists, since
+// a plain title could end in a number.
+func isSyncConflict(path string) bool {
+	name := filepath.Base(path)
+	if _, ok := conflictOriginalName(name); ok {
+		return true
+	}
+
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	if loc := numberedCopyPattern.FindStringIndex(stem); loc != nil {
+		original := filepath.Join(filepath.Dir(path), stem[:loc[0]]+filepath.Ext(name))
+		if _, err := os.Stat(original); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// globNotes is filepath.Glob without sync conflict copies
+func globNotes(pattern string) ([]string, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := files[:0]
+	for _, file := range files {
+		if !isSyncConflict(file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept, nil
+}
+
+// SyncConflict is a conflict copy and the file it conflicts with
+type SyncConflict struct {
+	Index    int
+	Path     string
+	Original string // empty if the original can't be found
+}
+
+func findSyncConflicts(config Config) ([]SyncConflict, error) {
+	dirs := []string{config.NotesDir}
+	if config.TaskDir != config.NotesDir {
+		dirs = append(dirs, config.TaskDir)
+	}
+
+	var conflicts []SyncConflict
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			if isSyncConflict(file) {
+				conflicts = append(conflicts, SyncConflict{Path: file, Original: conflictOriginal(file)})
+			}
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	for i := range conflicts {
+		conflicts[i].Index = i + 1
+	}
+	return conflicts, nil
+}
+
+// conflictOriginal finds the file a conflict copy belongs to: by name, or
+// by Denote identifier if the original has been renamed since
+func conflictOriginal(path string) string {
+	dir := filepath.Dir(path)
+	name := filepath.Base(path)
+
+	original, ok := conflictOriginalName(name)
+	if !ok {
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		if loc := numberedCopyPattern.FindStringIndex(stem); loc != nil {
+			original = stem[:loc[0]] + filepath.Ext(name)
+		}
+	}
+	if original != "" {
+		if _, err := os.Stat(filepath.Join(dir, original)); err == nil {
+			return filepath.Join(dir, original)
+		}
+	}
+
+	if len(name) >= len(denoteIDFormat) && isDenoteIdentifier(name[:len(denoteIDFormat)]) {
+		matches, _ := globNotes(filepath.Join(dir, name[:len(denoteIDFormat)]+"*.md"))
+		if len(matches) == 1 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// warnSyncConflicts mentions conflict copies after a listing, since they
+// are left out of it
+func warnSyncConflicts(config Config) {
+	conflicts, err := findSyncConflicts(config)
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+	fmt.Printf("\n%s %d sync %s not shown (run 'notes-cli conflicts')\n",
+		warning("!"), len(conflicts), pluralize(len(conflicts), "conflict", "conflicts"))
+}
+
+// frontmatterFields parses frontmatter into displayable values
+func frontmatterFields(frontmatter string) map[string]string {
+	raw := make(map[string]interface{})
+	yaml.Unmarshal([]byte(frontmatter), &raw)
+
+	fields := make(map[string]string)
+	for key, value := range raw {
+		switch v := value.(type) {
+		case []interface{}:
+			var items []string
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			fields[key] = strings.Join(items, ", ")
+		case nil:
+			fields[key] = ""
+		default:
+			fields[key] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
+
+// diffLines returns a line diff of a and b: lines prefixed with "-" are
+// only in a, "+" only in b and " " in both
+func diffLines(a, b []string) []string {
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "-"+a[i])
+			i++
+		default:
+			diff = append(diff, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+"+b[j])
+	}
+	return diff
+}
+
+func listSyncConflicts(config Config) error {
+	conflicts, err := findSyncConflicts(config)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Println("No sync conflicts found.")
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", bold(fmt.Sprintf("Sync conflicts (%d):", len(conflicts))))
+
+	for _, c := range conflicts {
+		fmt.Printf("%s %s\n", info(fmt.Sprintf("[%d]", c.Index)), filepath.Base(c.Path))
+		if c.Original == "" {
+			fmt.Printf("   %s\n\n", warning("original not found"))
+			continue
+		}
+		fmt.Printf("   %s %s\n", dim("original:"), filepath.Base(c.Original))
+
+		ours, err := os.ReadFile(c.Original)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", c.Original, err)
+		}
+		theirs, err := os.ReadFile(c.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", c.Path, err)
+		}
+		oursFM, oursBody := splitFrontmatter(string(ours))
+		theirsFM, theirsBody := splitFrontmatter(string(theirs))
+
+		// Frontmatter, field by field
+		oursFields, theirsFields := frontmatterFields(oursFM), frontmatterFields(theirsFM)
+		var keys []string
+		seen := make(map[string]bool)
+		for _, fields := range []map[string]string{oursFields, theirsFields} {
+			for key := range fields {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+		sort.Strings(keys)
+
+		var changed []string
+		for _, key := range keys {
+			if oursFields[key] != theirsFields[key] {
+				changed = append(changed, key)
+			}
+		}
+		if len(changed) > 0 {
+			fmt.Printf("   %s %s\n", dim("frontmatter"), dim("(original → conflict)"))
+			for _, key := range changed {
+				from, to := oursFields[key], theirsFields[key]
+				if from == "" {
+					from = "none"
+				}
+				if to == "" {
+					to = "none"
+				}
+				fmt.Printf("     %-10s %s → %s\n", key, from, to)
+			}
+		}
+
+		// Body, changed lines only (blank lines are noise here)
+		var bodyDiff []string
+		for _, line := range diffLines(strings.Split(strings.Trim(oursBody, "\n"), "\n"), strings.Split(strings.Trim(theirsBody, "\n"), "\n")) {
+			if strings.TrimSpace(line[1:]) == "" {
+				continue
+			}
+			switch line[0] {
+			case '-':
+				bodyDiff = append(bodyDiff, errorMsg(line))
+			case '+':
+				bodyDiff = append(bodyDiff, success(line))
+			}
+		}
+		if len(bodyDiff) > 0 {
+			fmt.Printf("   %s\n", dim("body"))
+			for _, line := range bodyDiff {
+				fmt.Printf("     %s\n", line)
+			}
+		}
+		if len(changed) == 0 && len(bodyDiff) == 0 {
+			fmt.Printf("   %s\n", dim("identical"))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("→ Resolve with: notes-cli conflicts resolve <n> -keep original|conflict|merge")
+	return nil
+}
+
+// mergeLogs adds the log entries only in theirs to ours, keeping the
+// newest-first order 'task log' writes
+func mergeLogs(ours, theirs string) (string, int) {
+	lines := strings.Split(ours, "\n")
+	have := make(map[string]bool)
+	for _, line := range lines {
+		have[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, line := range strings.Split(theirs, "\n") {
+		line = strings.TrimSpace(line)
+		if logLinePattern.MatchString(line) && !have[line] {
+			have[line] = true
+			missing = append(missing, line)
+		}
+	}
+	// Insert oldest first so newer entries end up above older ones
+	sort.SliceStable(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	_, body := splitFrontmatter(ours)
+	bodyStart := len(lines) - len(strings.Split(body, "\n"))
+
+	for _, entry := range missing {
+		date := logLinePattern.FindStringSubmatch(entry)[1]
+
+		// Before the first entry that is not newer, else after the last entry
+		insertAt, lastLog := -1, -1
+		for i := bodyStart; i < len(lines); i++ {
+			m := logLinePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+			if m == nil {
+				continue
+			}
+			lastLog = i
+			if m[1] <= date {
+				insertAt = i
+				break
+			}
+		}
+
+		var insert []string
+		switch {
+		case insertAt >= 0:
+			insert = []string{entry, ""}
+		case lastLog >= 0:
+			insertAt = lastLog + 1
+			insert = []string{"", entry}
+		default:
+			// No log yet: start one at the top of the body
+			insertAt = bodyStart
+			for insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+				insertAt++
+			}
+			insert = []string{entry, ""}
+			if insertAt >= len(lines) {
+				insert = []string{entry}
+			}
+		}
+
+		lines = append(lines[:insertAt], append(insert, lines[insertAt:]...)...)
+	}
+
+	return strings.Join(lines, "\n"), len(missing)
+}
+
+func resolveSyncConflict(config Config, ref string, keep string) error {
+	conflicts, err := findSyncConflicts(config)
+	if err != nil {
+		return err
+	}
+
+	var conflict *SyncConflict
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(conflicts) {
+		conflict = &conflicts[n-1]
+	} else {
+		for i := range conflicts {
+			if filepath.Base(conflicts[i].Path) == filepath.Base(ref) {
+				conflict = &conflicts[i]
+			}
+		}
+	}
+	if conflict == nil {
+		return fmt.Errorf("no sync conflict %s (run 'notes-cli conflicts' to list them)", ref)
+	}
+	if conflict.Original == "" {
+		return fmt.Errorf("the original of %s can't be found; rename or delete it by hand", filepath.Base(conflict.Path))
+	}
+
+	var message string
+	switch keep {
+	case "original":
+		message = "keep original"
+
+	case "conflict":
+		content, err := os.ReadFile(conflict.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read conflict copy: %w", err)
+		}
+		if err := os.WriteFile(conflict.Original, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", conflict.Original, err)
+		}
+		message = "keep conflict copy"
+
+	case "merge":
+		ours, err := os.ReadFile(conflict.Original)
+		if err != nil {
+			return fmt.Errorf("failed to read original: %w", err)
+		}
+		theirs, err := os.ReadFile(conflict.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read conflict copy: %w", err)
+		}
+		merged, added := mergeLogs(string(ours), string(theirs))
+		if added > 0 {
+			if err := os.WriteFile(conflict.Original, []byte(merged), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", conflict.Original, err)
+			}
+		}
+		fmt.Printf("  Merged %s from the conflict copy\n", count(added, pluralize(added, "log entry", "log entries")))
+		message = "merge logs"
+
+	default:
+		return fmt.Errorf("invalid -keep %q (use original, conflict or merge)", keep)
+	}
+
+	if err := os.Remove(conflict.Path); err != nil {
+		return fmt.Errorf("failed to remove conflict copy: %w", err)
+	}
+
+	gitAutoCommit(config, fmt.Sprintf("%s: resolve sync conflict (%s)", fileLabel(conflict.Original), message),
+		conflict.Original, conflict.Path)
+
+	fmt.Printf("%s Resolved: %s\n", success("✓"), message)
+	fmt.Printf("  %s %s\n", dim("Location:"), filename(conflict.Original))
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// splitFrontmatter splits file content into its YAML frontmatter (without the
+// --- delimiters) and the body that follows. Content without frontmatter is
+// returned entirely as body.
+func splitFrontmatter(content string) (string, string) {
+	lines := strings.Split(content, "\n")
+	frontmatterStart := -1
+
+	for i, line := range lines {
+		if line == "---" {
+			if frontmatterStart == -1 {
+				frontmatterStart = i
+			} else {
+				frontmatter := strings.Join(lines[frontmatterStart+1:i], "\n")
+				body := strings.Join(lines[i+1:], "\n")
+				return frontmatter, body
+			}
+		} else if frontmatterStart == -1 && strings.TrimSpace(line) != "" {
+			// Content before any delimiter means there is no frontmatter
+			break
+		}
+	}
+
+	return "", content
+}
+
+// readNoteBody returns the Markdown body of a file with the frontmatter removed
+func readNoteBody(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	_, body := splitFrontmatter(string(content))
+	return strings.TrimLeft(body, "\n"), nil
+}
+
+// vaultFiles returns every Markdown file in the notes and task directories
+func vaultFiles(config Config) ([]string, error) {
+	files, err := globNotes(filepath.Join(config.NotesDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.TaskDir != config.NotesDir {
+		taskFiles, err := globNotes(filepath.Join(config.TaskDir, "*.md"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, taskFiles...)
+	}
+
+	return files, nil
+}
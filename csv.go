@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns lists the columns export csv can write, in the default order
+var csvColumns = []string{
+	"task_id", "title", "status", "priority", "due_date", "start_date", "estimate",
+	"project", "area", "assignee", "remind", "tags", "created", "completed_date", "modified", "path", "denote_id",
+}
+
+const defaultCSVColumns = "task_id,title,status,priority,due_date,start_date,estimate,project,area,assignee,remind,tags"
+
+// csvColumnAliases maps common spreadsheet headers to column names
+var csvColumnAliases = map[string]string{
+	"id":        "task_id",
+	"task":      "title",
+	"name":      "title",
+	"due":       "due_date",
+	"start":     "start_date",
+	"p":         "priority",
+	"tag":       "tags",
+	"keywords":  "tags",
+	"owner":     "assignee",
+	"reminder":  "remind",
+	"reminders": "remind",
+}
+
+// normalizeCSVColumn turns a header like "Due Date" into due_date
+func normalizeCSVColumn(header string) string {
+	name := strings.ToLower(strings.TrimSpace(header))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+	if alias, ok := csvColumnAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func isCSVColumn(name string) bool {
+	for _, c := range csvColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSVColumns(list string) ([]string, error) {
+	var columns []string
+	for _, c := range strings.Split(list, ",") {
+		if strings.TrimSpace(c) == "" {
+			continue
+		}
+		name := normalizeCSVColumn(c)
+		if !isCSVColumn(name) {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", c, strings.Join(csvColumns, ", "))
+		}
+		columns = append(columns, name)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return columns, nil
+}
+
+func csvValue(task TaskInfo, column string) string {
+	switch column {
+	case "task_id":
+		return strconv.Itoa(task.TaskID)
+	case "title":
+		return task.Note.Title
+	case "status":
+		return task.Status
+	case "priority":
+		return task.Priority
+	case "due_date":
+		return task.DueDate
+	case "start_date":
+		return task.StartDate
+	case "estimate":
+		if task.Estimate == 0 {
+			return ""
+		}
+		return strconv.Itoa(task.Estimate)
+	case "project":
+		return task.Project
+	case "area":
+		return task.Area
+	case "assignee":
+		return task.Assignee
+	case "remind":
+		return task.Remind
+	case "tags":
+		var tags []string
+		for _, tag := range task.Note.Tags {
+			if tag != "task" {
+				tags = append(tags, tag)
+			}
+		}
+		return strings.Join(tags, ",")
+	case "created":
+		if created, err := time.ParseInLocation(denoteIDFormat, task.Note.ID, time.Local); err == nil {
+			return created.Format("2006-01-02")
+		}
+		return ""
+	case "completed_date":
+		return task.CompletedDate
+	case "modified":
+		return task.ModTime.Format("2006-01-02")
+	case "path":
+		return task.Path
+	case "denote_id":
+		return task.Note.ID
+	}
+	return ""
+}
+
+func exportCSV(config Config, filters TaskFilters, columnList, output string) error {
+	columns, err := parseCSVColumns(columnList)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(columns)
+	for _, task := range tasks {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvValue(task, column)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	if output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", success("✓"), count(len(tasks), pluralize(len(tasks), "task", "tasks")), output)
+
+	return nil
+}
+
+// CSVImportOptions controls import csv
+type CSVImportOptions struct {
+	DryRun         bool
+	CreateProjects bool
+}
+
+// csvRow is a validated row ready to become a task
+type csvRow struct {
+	Line  int
+	Title string
+	Meta  TaskMetadata
+	Tags  []string
+}
+
+// parseCSVRow validates one row. Every problem in the row is reported, not
+// just the first.
+func parseCSVRow(header []string, record []string) (*csvRow, []string) {
+	row := &csvRow{}
+	var problems []string
+
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case "title":
+			row.Title = value
+		case "status":
+			value = strings.ToLower(value)
+			if !isValidStatus(value) {
+				problems = append(problems, fmt.Sprintf("invalid status %q", value))
+			}
+			row.Meta.Status = value
+		case "priority":
+			value = strings.ToLower(value)
+			if !strings.HasPrefix(value, "p") {
+				value = "p" + value
+			}
+			if !isValidPriority(value) {
+				problems = append(problems, fmt.Sprintf("invalid priority %q", record[i]))
+			}
+			row.Meta.Priority = value
+		case "due_date", "start_date":
+			date, err := parseDate(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("invalid %s %q", strings.Replace(column, "_", " ", 1), value))
+			}
+			if column == "due_date" {
+				row.Meta.DueDate = date
+			} else {
+				row.Meta.StartDate = date
+			}
+		case "estimate":
+			estimate, err := strconv.Atoi(value)
+			if err != nil || !isValidEstimate(estimate) {
+				problems = append(problems, fmt.Sprintf("invalid estimate %q (use 1, 2, 3, 5, 8 or 13)", value))
+			}
+			row.Meta.Estimate = estimate
+		case "project":
+			row.Meta.Project = value
+		case "area":
+			row.Meta.Area = value
+		case "assignee":
+			row.Meta.Assignee = value
+		case "remind":
+			if err := checkRemind(value); err != nil {
+				problems = append(problems, err.Error())
+			}
+			row.Meta.Remind = value
+		case "tags":
+			for _, t := range strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || r == ';' || r == ' '
+			}) {
+				if tag := sluggifyKeyword(t); tag != "" && tag != "task" && !containsTag(row.Tags, tag) {
+					row.Tags = append(row.Tags, tag)
+				}
+			}
+		}
+	}
+
+	if row.Title == "" {
+		problems = append(problems, "no title")
+	}
+
+	return row, problems
+}
+
+func importCSV(config Config, path string, opts CSVImportOptions) error {
+	data, err := readImportFile(path)
+	if err != nil {
+		return err
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	headers, err := r.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%s is empty", path)
+	} else if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	header := make([]string, len(headers))
+	hasTitle := false
+	var ignored []string
+	for i, h := range headers {
+		name := normalizeCSVColumn(strings.TrimPrefix(h, "\ufeff"))
+		switch name {
+		case "title":
+			hasTitle = true
+		case "status", "priority", "due_date", "start_date", "estimate", "project", "area", "assignee", "remind", "tags":
+		default:
+			// IDs, paths and dates are assigned when the task is created
+			if h != "" {
+				ignored = append(ignored, h)
+			}
+			name = ""
+		}
+		header[i] = name
+	}
+	if !hasTitle {
+		return fmt.Errorf("no title column in %s (headers: %s)", path, strings.Join(headers, ", "))
+	}
+	if len(ignored) > 0 {
+		fmt.Println(dim(fmt.Sprintf("Ignoring columns: %s", strings.Join(ignored, ", "))))
+	}
+
+	// Validate every row before creating anything
+	var rows []*csvRow
+	var problems []string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row, rowProblems := parseCSVRow(header, record)
+		if len(rowProblems) > 0 {
+			problems = append(problems, fmt.Sprintf("line %d: %s", line, strings.Join(rowProblems, "; ")))
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+
+	// Projects the rows refer to that don't exist yet
+	existing := make(map[string]string)
+	if projects, err := findProjects(config, ProjectFilters{All: true}); err == nil {
+		for _, project := range projects {
+			existing[strings.ToLower(project.Note.Title)] = project.Note.Title
+		}
+	}
+	var missing []string
+	for _, row := range rows {
+		if row.Meta.Project == "" {
+			continue
+		}
+		key := strings.ToLower(row.Meta.Project)
+		if name, ok := existing[key]; ok {
+			row.Meta.Project = name
+			continue
+		}
+		existing[key] = row.Meta.Project
+		missing = append(missing, row.Meta.Project)
+	}
+
+	if opts.DryRun {
+		fmt.Println(dim("Dry run: nothing will be changed"))
+		for _, row := range rows {
+			fmt.Printf("  %s %s\n", info("+"), row.Title)
+		}
+		for _, name := range missing {
+			if opts.CreateProjects {
+				fmt.Printf("  %s project %s\n", info("+"), name)
+			}
+		}
+		fmt.Printf("%s %s valid", success("✓"), count(len(rows), pluralize(len(rows), "row", "rows")))
+		if len(problems) > 0 {
+			fmt.Printf(", %d invalid", len(problems))
+		}
+		fmt.Println()
+	} else {
+		gitStartBatch()
+		if opts.CreateProjects {
+			for _, name := range missing {
+				if _, _, err := writeProject(config, name, ProjectMetadata{}, nil, "", true); err != nil {
+					gitFinishBatch(config, importCommitMessage(path, 0))
+					return fmt.Errorf("failed to create project %s: %w", name, err)
+				}
+				fmt.Printf("  %s project %s\n", info("+"), name)
+			}
+		}
+
+		imported := 0
+		for _, row := range rows {
+			task, err := createTaskSilently(config, row.Title, row.Meta, row.Tags, "")
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %v", row.Line, err))
+				continue
+			}
+			fmt.Printf("  %s #%d %s\n", info("+"), task.TaskID, row.Title)
+			imported++
+		}
+		gitFinishBatch(config, importCommitMessage(path, imported))
+		fmt.Printf("%s Imported %s\n", success("✓"), count(imported, pluralize(imported, "task", "tasks")))
+	}
+
+	if len(missing) > 0 && !opts.CreateProjects {
+		fmt.Printf("%s %d %s not found (use -create-projects to create them): %s\n",
+			warning("!"), len(missing), pluralize(len(missing), "project", "projects"), strings.Join(missing, ", "))
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("%s %d %s skipped:\n", warning("!"), len(problems), pluralize(len(problems), "row", "rows"))
+		for _, problem := range problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+
+	return nil
+}
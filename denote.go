@@ -3,40 +3,33 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"text/template"
 )
 
-// Denote filename format: DATE--TITLE__TAGS.md
-// Example: 20231024T120000--my-note-title__tag1_tag2.md
+// Denote filename format: DATE==SIGNATURE--TITLE__TAGS.md
+// Example: 20231024T120000==1a--my-note-title__tag1_tag2.md
+// See denote_filename.go for the full grammar.
 
 type Note struct {
-	ID    string
-	Title string
-	Tags  []string
+	ID        string
+	Signature string
+	Title     string
+	Tags      []string
 }
 
 func (n Note) Filename() string {
-	filename := n.ID + "--" + slugify(n.Title)
-	if len(n.Tags) > 0 {
-		// Sanitize tags to remove @ symbols from filenames
-		sanitizedTags := make([]string, len(n.Tags))
-		for i, tag := range n.Tags {
-			sanitizedTags[i] = strings.ReplaceAll(tag, "@", "")
-		}
-		filename += "__" + strings.Join(sanitizedTags, "_")
-	}
-	return filename + ".md"
+	// Keywords are sluggified for the filename only; frontmatter keeps tags as written
+	return buildFilename(n.ID, n.Signature, n.Title, n.Tags) + ".md"
 }
 
 func (n Note) Frontmatter() string {
 	tmpl := `---
 id: "{{ .ID }}"
 title: "{{ .Title }}"
-date: {{ .Date }}
+date: {{ .Date }}{{ if .Signature }}
+signature: "{{ .Signature }}"{{ end }}
 tags:{{ range .Tags }}
   - {{ . }}{{ end }}
 ---
@@ -46,27 +39,20 @@ tags:{{ range .Tags }}
 	
 	var result strings.Builder
 	t.Execute(&result, map[string]interface{}{
-		"ID":    n.ID,
-		"Title": n.Title,
-		"Date":  formatDateFromID(n.ID),
-		"Tags":  n.Tags,
+		"ID":        n.ID,
+		"Signature": n.Signature,
+		"Title":     n.Title,
+		"Date":      formatDateFromID(n.ID),
+		"Tags":      n.Tags,
 	})
 	
 	return result.String()
 }
 
 func slugify(s string) string {
-	// Convert to lowercase
-	s = strings.ToLower(s)
-	
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	s = reg.ReplaceAllString(s, "-")
-	
-	// Remove leading/trailing hyphens
-	s = strings.Trim(s, "-")
-	
-	return s
+	// Lowercase, keep letters and digits, and collapse everything else
+	// into single hyphens (Denote's title slug rules)
+	return sluggifyWith(s, '-')
 }
 
 func formatDateFromID(id string) string {
@@ -90,63 +76,101 @@ func parseTags(tagsStr string) []string {
 	return tags
 }
 
-func createNote(config Config, title string, tagsStr string, noEdit bool) error {
-	// Ensure notes directory exists
-	if err := os.MkdirAll(config.NotesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create notes directory: %w", err)
-	}
-	
+func createNote(config Config, title string, tags []string, signature string, body string, noEdit bool) error {
 	// Create note
 	note := Note{
-		ID:    generateDenoteID(),
-		Title: title,
-		Tags:  parseTags(tagsStr),
+		ID:        generateDenoteID(),
+		Signature: sluggifySignature(signature),
+		Title:     title,
+		Tags:      tags,
 	}
 	
-	// Generate filename and path
+	filepath, err := writeNewNote(config.NotesDir, note, body)
+	if err != nil {
+		return err
+	}
 	filename := note.Filename()
-	filepath := filepath.Join(config.NotesDir, filename)
+	
+	// Open in editor unless --no-edit flag is set
+	if !noEdit {
+		if err := openInEditor(filepath); err != nil {
+			return err
+		}
+	}
+
+	gitAutoCommit(config, fmt.Sprintf("%s: create", fileLabel(filepath)), filepath)
+
+	fmt.Printf("✓ Note created: %s\n", note.Title)
+	fmt.Printf("  Location: %s\n", filepath)
+	if !noEdit {
+		fmt.Printf("→ Note opened in editor\n")
+	} else {
+		fmt.Printf("→ Run 'notes-cli edit %s' to add content\n", filename)
+	}
+	return nil
+}
+
+// writeNewNote creates a new note file in dir with frontmatter followed by
+// body, and returns its path
+func writeNewNote(dir string, note Note, body string) (string, error) {
+	// Ensure notes directory exists
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	
+	// Generate filename and path
+	path := filepath.Join(dir, note.Filename())
 	
 	// Check if file already exists
-	if _, err := os.Stat(filepath); err == nil {
-		return fmt.Errorf("file already exists: %s", filepath)
+	if _, err := os.Stat(path); err == nil {
+		return "", invalidf("file already exists: %s", path)
 	}
 	
 	// Create file with frontmatter
-	file, err := os.Create(filepath)
+	file, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return "", fmt.Errorf("failed to create file: %w", err)
 	}
 	
-	_, err = file.WriteString(note.Frontmatter())
+	_, err = file.WriteString(note.Frontmatter() + body)
 	file.Close()
 	if err != nil {
-		return fmt.Errorf("failed to write frontmatter: %w", err)
+		return "", fmt.Errorf("failed to write frontmatter: %w", err)
 	}
 	
-	// Open in editor unless --no-edit flag is set
-	if !noEdit {
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi" // fallback
-		}
-		
-		cmd := exec.Command(editor, filepath)
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to open editor: %w", err)
+	return path, nil
+}
+
+// updateNoteFile rewrites a plain note's frontmatter from note, and its body
+// if body isn't nil, renaming the file to match. It returns the note's
+// (possibly new) path.
+func updateNoteFile(config Config, path string, note Note, body *string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	_, oldBody := splitFrontmatter(string(content))
+	if body == nil {
+		body = &oldBody
+	}
+	
+	newPath := filepath.Join(filepath.Dir(path), note.Filename())
+	if newPath != path {
+		if _, err := os.Stat(newPath); err == nil {
+			return "", invalidf("target file already exists: %s", newPath)
 		}
 	}
 	
-	fmt.Printf("✓ Note created: %s\n", note.Title)
-	fmt.Printf("  Location: %s\n", filepath)
-	if !noEdit {
-		fmt.Printf("→ Note opened in editor\n")
-	} else {
-		fmt.Printf("→ Run 'notes-cli edit %s' to add content\n", filename)
+	newContent := note.Frontmatter() + strings.TrimLeft(*body, "\n")
+	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
 	}
-	return nil
-}
\ No newline at end of file
+	if newPath != path {
+		if err := os.Rename(path, newPath); err != nil {
+			return "", fmt.Errorf("failed to rename file: %w", err)
+		}
+	}
+	
+	gitAutoCommit(config, fmt.Sprintf("%s: update", fileLabel(newPath)), path, newPath)
+	return newPath, nil
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Denote file names are built from up to four components, each introduced by
+// its own delimiter:
+//
+//   identifier  20231024T120000   (prefixed with @@ when it is not first)
+//   signature   ==1a2
+//   title       --my-note-title
+//   keywords    __tag1_tag2
+//
+// The default order is identifier, signature, title, keywords, which gives
+// 20231024T120000==1a2--my-note-title__tag1_tag2.md
+
+const (
+	componentIdentifier = "identifier"
+	componentSignature  = "signature"
+	componentTitle      = "title"
+	componentKeywords   = "keywords"
+)
+
+var defaultFilenameOrder = []string{
+	componentIdentifier,
+	componentSignature,
+	componentTitle,
+	componentKeywords,
+}
+
+// filenameOrder is the component order used when generating file names.
+// It is set from the file_name_order config option by loadConfig.
+var filenameOrder = defaultFilenameOrder
+
+var componentDelimiters = map[string]string{
+	"@@": componentIdentifier,
+	"==": componentSignature,
+	"--": componentTitle,
+	"__": componentKeywords,
+}
+
+// normalizeFilenameOrder validates a configured component order. Unknown or
+// duplicate components are an error; components that are left out are
+// appended in the default order so no part of a file name is ever dropped.
+func normalizeFilenameOrder(order []string) ([]string, error) {
+	if len(order) == 0 {
+		return defaultFilenameOrder, nil
+	}
+
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, c := range order {
+		c = strings.ToLower(strings.TrimSpace(c))
+		switch c {
+		case componentIdentifier, componentSignature, componentTitle, componentKeywords:
+		default:
+			return nil, fmt.Errorf("unknown file name component: %q", c)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate file name component: %q", c)
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+
+	for _, c := range defaultFilenameOrder {
+		if !seen[c] {
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
+
+// buildFilename assembles a Denote file name (without extension) in the
+// configured component order. Empty components are omitted.
+func buildFilename(id, signature, title string, keywords []string) string {
+	var b strings.Builder
+
+	for _, c := range filenameOrder {
+		switch c {
+		case componentIdentifier:
+			if id == "" {
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteString("@@")
+			}
+			b.WriteString(id)
+		case componentSignature:
+			if s := sluggifySignature(signature); s != "" {
+				b.WriteString("==" + s)
+			}
+		case componentTitle:
+			if s := slugify(title); s != "" {
+				b.WriteString("--" + s)
+			}
+		case componentKeywords:
+			if s := joinKeywords(keywords); s != "" {
+				b.WriteString("__" + s)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// sluggifyKeyword applies Denote's keyword rules: lowercase, with every
+// character that is not a letter or digit removed (so hyphens and @ go too).
+func sluggifyKeyword(k string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(k) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func joinKeywords(keywords []string) string {
+	slugs := []string{}
+	seen := make(map[string]bool)
+	for _, k := range keywords {
+		s := sluggifyKeyword(k)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		slugs = append(slugs, s)
+	}
+	return strings.Join(slugs, "_")
+}
+
+// sluggifySignature lowercases a signature and turns every run of
+// non-alphanumeric characters into a single "=", e.g. "1a 2" -> "1a=2".
+func sluggifySignature(s string) string {
+	return sluggifyWith(s, '=')
+}
+
+func sluggifyWith(s string, sep rune) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingSep && b.Len() > 0 {
+				b.WriteRune(sep)
+			}
+			pendingSep = false
+			b.WriteRune(r)
+		} else {
+			pendingSep = true
+		}
+	}
+	return b.String()
+}
+
+// isDenoteIdentifier reports whether s has the form 20060102T150405
+func isDenoteIdentifier(s string) bool {
+	if len(s) != len(denoteIDFormat) || s[8] != 'T' {
+		return false
+	}
+	for i, r := range s {
+		if i == 8 {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFilename breaks a Denote file name into its components, keyed by
+// component name. Components may appear in any order.
+func splitFilename(filename string) (map[string]string, error) {
+	// Strip the extension at the last dot, so dots in a title or signature
+	// stay put, and the note's own extension under an encrypted one
+	// (.md.gpg)
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	switch filepath.Ext(name) {
+	case ".md", ".org", ".txt":
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	components := make(map[string]string)
+	current := ""
+	start := 0
+
+	// A leading identifier has no delimiter
+	if len(name) >= len(denoteIDFormat) && isDenoteIdentifier(name[:len(denoteIDFormat)]) {
+		current = componentIdentifier
+	}
+
+	flush := func(end int) error {
+		if current == "" {
+			if end > start {
+				return fmt.Errorf("unexpected text %q in file name", name[start:end])
+			}
+			return nil
+		}
+		if _, dup := components[current]; dup {
+			return fmt.Errorf("duplicate %s component in file name", current)
+		}
+		components[current] = name[start:end]
+		return nil
+	}
+
+	for i := 0; i+1 < len(name); i++ {
+		component, ok := componentDelimiters[name[i:i+2]]
+		if !ok {
+			continue
+		}
+		// Runs such as "---" belong to the previous component
+		if i+2 < len(name) && name[i+2] == name[i] {
+			continue
+		}
+		if err := flush(i); err != nil {
+			return nil, err
+		}
+		current = component
+		start = i + 2
+		i++
+	}
+	if err := flush(len(name)); err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// compareSignatures orders Luhmann-style signatures such as 1, 1a, 1a2, 1b,
+// 2, 10: digit runs compare numerically and letter runs alphabetically.
+// Notes without a signature sort after those with one.
+func compareSignatures(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	pa := signatureParts(a)
+	pb := signatureParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			// Numbers sort before letters at the same depth
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return len(pa) - len(pb)
+}
+
+// signatureParts splits "1a=12b" into ["1", "a", "12", "b"]
+func signatureParts(s string) []string {
+	var parts []string
+	var current strings.Builder
+	lastDigit := false
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		isDigit := unicode.IsDigit(r)
+		if current.Len() > 0 && isDigit != lastDigit {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		current.WriteRune(r)
+		lastDigit = isDigit
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
+
+// signatureFromFilename returns the signature component of a file name, if any
+func signatureFromFilename(path string) string {
+	components, err := splitFilename(filepath.Base(path))
+	if err != nil {
+		return ""
+	}
+	return components[componentSignature]
+}
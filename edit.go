@@ -69,4 +69,23 @@ func editTask(config Config, arg string) error {
 	
 	fmt.Printf("Edited: %s\n", taskPath)
 	return nil
-}
\ No newline at end of file
+}
+
+// openInEditor opens a file in $EDITOR (falling back to vi)
+func openInEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	
+	cmd := exec.Command(editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open editor: %w", err)
+	}
+	
+	return nil
+}
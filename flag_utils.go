@@ -1,35 +1,78 @@
 package main
 
 import (
-	"strconv"
+	"flag"
 )
 
-// parseSoonFlag parses the -soon flag which can be used with or without a value
-// Returns the number of days to use, or 0 if not specified
-func parseSoonFlag(args []string) (int, []string) {
-	newArgs := []string{}
-	soonDays := 0
-	
-	for i := 0; i < len(args); i++ {
-		if args[i] == "-soon" {
-			// Check if next arg exists and is a number
-			if i+1 < len(args) {
-				if days, err := strconv.Atoi(args[i+1]); err == nil && days > 0 {
-					// Next arg is a valid number, use it
-					soonDays = days
-					i++ // Skip the number
-				} else {
-					// Next arg is not a number or doesn't exist, use -1 to indicate "use config default"
-					soonDays = -1
-				}
-			} else {
-				// -soon is the last argument, use config default
-				soonDays = -1
-			}
-		} else {
-			newArgs = append(newArgs, args[i])
-		}
+// TaskFilterFlags are the 'task list' filter flags, shared by commands that
+// select tasks the same way
+type TaskFilterFlags struct {
+	status   *string
+	priority *string
+	project  *string
+	area     *string
+	tag      *string
+	due      *string
+	overdue  *bool
+	all      *bool
+	sortBy   *string
+	reverse  *bool
+	p1       *bool
+	p2       *bool
+	p3       *bool
+	soon     *soonFlag
+}
+
+func addTaskFilterFlags(fs *flag.FlagSet) *TaskFilterFlags {
+	f := &TaskFilterFlags{
+		status:   fs.String("status", "", "Filter by status (open, done, paused, delegated, dropped)"),
+		priority: fs.String("p", "", "Filter by priority (p1, p2, p3)"),
+		project:  fs.String("project", "", "Filter by project"),
+		area:     fs.String("area", "", "Filter by area"),
+		tag:      fs.String("tag", "", "Filter by tag"),
+		due:      fs.String("due", "", "Filter by due date (today, week, month, YYYY-MM-DD)"),
+		overdue:  fs.Bool("overdue", false, "Show only overdue tasks"),
+		all:      fs.Bool("all", false, "Show all tasks (default: open only)"),
+		sortBy:   fs.String("sort", "modified", "Sort by: modified, priority, due"),
+		reverse:  fs.Bool("reverse", false, "Reverse sort order"),
+		p1:       fs.Bool("p1", false, "Show only P1 tasks"),
+		p2:       fs.Bool("p2", false, "Show only P2 tasks"),
+		p3:       fs.Bool("p3", false, "Show only P3 tasks"),
+		soon:     new(soonFlag),
+	}
+	fs.Var(f.soon, "soon", "Show tasks due within `N` days (default: soon_horizon)")
+	return f
+}
+
+// filters returns the parsed flags as filters. Like 'task list', they only
+// match open tasks unless -all or -status is given.
+func (f *TaskFilterFlags) filters(config Config) TaskFilters {
+	// Handle priority shortcuts
+	priority := *f.priority
+	if *f.p1 {
+		priority = "p1"
+	} else if *f.p2 {
+		priority = "p2"
+	} else if *f.p3 {
+		priority = "p3"
+	}
+
+	status := *f.status
+	if !*f.all && status == "" {
+		status = "open"
+	}
+
+	return TaskFilters{
+		Status:    status,
+		Priority:  priority,
+		Project:   *f.project,
+		Area:      *f.area,
+		Tag:       *f.tag,
+		DueFilter: *f.due,
+		Overdue:   *f.overdue,
+		All:       *f.all,
+		SortBy:    *f.sortBy,
+		Reverse:   *f.reverse,
+		SoonDays:  f.soon.days(config),
 	}
-	
-	return soonDays, newArgs
-}
\ No newline at end of file
+}
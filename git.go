@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// GitConfig controls committing vault changes ([git] in config.toml)
+type GitConfig struct {
+	AutoCommit bool `toml:"auto_commit"`
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitAutoCommit commits the given paths (including ones just deleted or
+// renamed away) if [git] auto_commit is on. Other changes in the repository,
+// staged or not, are left alone. Failures are reported but never fail the
+// command that made the change.
+func gitAutoCommit(config Config, message string, paths ...string) {
+	if !config.TOMLConfig.Git.AutoCommit || len(paths) == 0 {
+		return
+	}
+	if gitBatch != nil {
+		*gitBatch = append(*gitBatch, paths...)
+		return
+	}
+	if err := gitCommitPaths(message, paths); err != nil {
+		fmt.Printf("%s Not committed: %v\n", warning("!"), err)
+	}
+}
+
+// gitBatch collects the paths gitAutoCommit would have committed while a
+// batch is open; it is nil otherwise
+var gitBatch *[]string
+
+// gitStartBatch holds back auto-commits until gitFinishBatch, so a command
+// that writes many files (like an import) makes a single commit
+func gitStartBatch() {
+	gitBatch = &[]string{}
+}
+
+// gitFinishBatch commits everything collected since gitStartBatch
+func gitFinishBatch(config Config, message string) {
+	if gitBatch == nil {
+		return
+	}
+	paths := *gitBatch
+	gitBatch = nil
+	gitAutoCommit(config, message, paths...)
+}
+
+func gitCommitPaths(message string, paths []string) error {
+	dir := filepath.Dir(paths[0])
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("%s is not in a git repository", dir)
+	}
+
+	// Only paths that exist or that git knows about can be staged
+	var pathspec []string
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(abs); err != nil {
+			if tracked, _ := git(top, "ls-files", "--", abs); tracked == "" {
+				continue
+			}
+		}
+		pathspec = append(pathspec, abs)
+	}
+	if len(pathspec) == 0 {
+		return nil
+	}
+
+	if _, err := git(top, append([]string{"add", "-A", "--"}, pathspec...)...); err != nil {
+		return err
+	}
+
+	// Nothing changed (e.g. an update that set a field to its current value)
+	if _, err := git(top, append([]string{"diff", "--cached", "--quiet", "--"}, pathspec...)...); err == nil {
+		return nil
+	}
+
+	_, err = git(top, append([]string{"commit", "-q", "-m", message, "--"}, pathspec...)...)
+	return err
+}
+
+func isTaskNumber(ref string) bool {
+	_, err := strconv.Atoi(ref)
+	return err == nil
+}
+
+// commitSubject shortens free text (like a log entry) for a commit message
+func commitSubject(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) > 60 {
+		runes := []rune(text)
+		text = string(runes[:57]) + "..."
+	}
+	return text
+}
+
+// fileLabel names a file in commit messages: "task #42", "project #3" or
+// the note's title
+func fileLabel(path string) string {
+	note, err := noteIdentity(path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+
+	switch noteKind(note) {
+	case "task":
+		if task, err := parseTaskFile(path); err == nil && task.TaskID != 0 {
+			return fmt.Sprintf("task #%d", task.TaskID)
+		}
+	case "project":
+		if project, err := parseProjectFile(path); err == nil && project.ProjectID != 0 {
+			return fmt.Sprintf("project #%d", project.ProjectID)
+		}
+	}
+	return fmt.Sprintf("note %q", note.Title)
+}
+
+// changeSummary collects the changes described in a commit message
+type changeSummary []string
+
+func (c *changeSummary) field(name, old, new string) {
+	if old == new {
+		return
+	}
+	if old == "" {
+		old = "none"
+	}
+	if new == "" {
+		new = "none"
+	}
+	*c = append(*c, fmt.Sprintf("%s %s → %s", name, old, new))
+}
+
+func (c *changeSummary) tags(before, after []string) {
+	var tagChanges []string
+	for _, tag := range after {
+		if !containsTag(before, tag) {
+			tagChanges = append(tagChanges, "+"+tag)
+		}
+	}
+	for _, tag := range before {
+		if !containsTag(after, tag) {
+			tagChanges = append(tagChanges, "-"+tag)
+		}
+	}
+	if len(tagChanges) > 0 {
+		*c = append(*c, "tags "+strings.Join(tagChanges, " "))
+	}
+}
+
+func (c changeSummary) String() string {
+	if len(c) == 0 {
+		return "update"
+	}
+	return strings.Join(c, ", ")
+}
+
+// describeTaskChanges summarizes frontmatter changes for a commit message,
+// e.g. "status open → done, tags +urgent"
+func describeTaskChanges(before, after TaskFrontmatter) string {
+	var changes changeSummary
+	changes.field("title", before.Title, after.Title)
+	changes.field("status", before.Status, after.Status)
+	changes.field("priority", before.Priority, after.Priority)
+	changes.field("due", before.DueDate, after.DueDate)
+	changes.field("start", before.StartDate, after.StartDate)
+	if before.Estimate != after.Estimate {
+		changes.field("estimate", fmt.Sprint(before.Estimate), fmt.Sprint(after.Estimate))
+	}
+	changes.field("project", before.Project, after.Project)
+	changes.field("area", before.Area, after.Area)
+	changes.field("assignee", before.Assignee, after.Assignee)
+	changes.field("remind", before.Remind, after.Remind)
+	changes.field("completed", before.CompletedDate, after.CompletedDate)
+	changes.tags(before.Tags, after.Tags)
+	return changes.String()
+}
+
+// describeProjectChanges is describeTaskChanges for projects
+func describeProjectChanges(before, after ProjectFrontmatter) string {
+	var changes changeSummary
+	changes.field("title", before.Title, after.Title)
+	changes.field("status", before.Status, after.Status)
+	changes.field("priority", before.Priority, after.Priority)
+	changes.field("due", before.DueDate, after.DueDate)
+	changes.field("start", before.StartDate, after.StartDate)
+	changes.field("area", before.Area, after.Area)
+	changes.tags(before.Tags, after.Tags)
+	return changes.String()
+}
+
+// showHistory prints the commits that touched a file, following renames
+func showHistory(config Config, ref string, isProject bool, patch bool) error {
+	var path string
+	var err error
+	switch {
+	case isProject:
+		path, err = resolveProjectArg(config, ref)
+	case isTaskNumber(ref):
+		path, err = resolveTaskArg(config, ref)
+	default:
+		path, err = resolveNoteRef(config, ref)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if _, err := git(dir, "rev-parse", "--show-toplevel"); err != nil {
+		return fmt.Errorf("%s is not in a git repository", dir)
+	}
+
+	if patch {
+		cmd := exec.Command("git", "-C", dir, "log", "--follow", "-p", "--", filepath.Base(path))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	out, err := git(dir, "log", "--follow", "--date=short", "--format=%h%x1f%ad%x1f%an%x1f%s", "--", filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n\n", bold("History of"), fileLabel(path))
+	if out == "" {
+		fmt.Println(dim("No commits yet (enable auto_commit in the [git] section of config.toml)"))
+		return nil
+	}
+
+	lines := strings.Split(out, "\n")
+	for _, line := range lines {
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		fmt.Printf("%s %s %s %s\n", info(parts[0]), parts[1], parts[3], dim("("+parts[2]+")"))
+	}
+	fmt.Printf("\n%s\n", count(len(lines), pluralize(len(lines), "commit", "commits")))
+
+	return nil
+}
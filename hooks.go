@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Hooks run on task and project lifecycle events:
+//
+//	pre-create  post-create
+//	pre-update  post-update
+//	pre-done    post-done    (status set to done, or completed for projects)
+//	pre-delete  post-delete
+//	pre-log     post-log
+//
+// A hook is an executable named after the event in ~/.config/notes-cli/hooks/
+// (or any executable in hooks/<event>.d/), or a shell command in the [hooks]
+// section of config.toml. It gets a HookPayload as JSON on stdin. A pre-hook
+// that exits non-zero stops the change.
+
+// HookPayload is what a hook receives on stdin. Old is null for create and
+// New is null for delete.
+type HookPayload struct {
+	Event string      `json:"event"`
+	Kind  string      `json:"kind"` // task or project
+	ID    string      `json:"id"`
+	Title string      `json:"title"`
+	Tags  []string    `json:"tags"`
+	Path  string      `json:"path"`
+	Old   interface{} `json:"old"` // TaskMetadata or ProjectMetadata
+	New   interface{} `json:"new"`
+	Log   string      `json:"log,omitempty"` // the entry, for log events
+}
+
+// hookEnvVar is set while a hook runs, so notes-cli commands run by a hook
+// don't trigger hooks again
+const hookEnvVar = "NOTES_CLI_HOOK"
+
+// isHookEvent reports whether event is one hooks can run on
+func isHookEvent(event string) bool {
+	action := strings.TrimPrefix(strings.TrimPrefix(event, "pre-"), "post-")
+	if action == event {
+		return false
+	}
+	switch action {
+	case "create", "update", "done", "delete", "log":
+		return true
+	}
+	return false
+}
+
+func hooksDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "notes-cli", "hooks")
+}
+
+// taskHookPayload describes a task for hooks
+func taskHookPayload(path string, fm TaskFrontmatter) HookPayload {
+	meta := fm.TaskMetadata
+	return HookPayload{Kind: "task", ID: fm.ID, Title: fm.Title, Tags: fm.Tags, Path: path, Old: &meta, New: &meta}
+}
+
+// projectHookPayload describes a project for hooks
+func projectHookPayload(path string, fm ProjectFrontmatter) HookPayload {
+	meta := fm.ProjectMetadata
+	return HookPayload{Kind: "project", ID: fm.ID, Title: fm.Title, Tags: fm.Tags, Path: path, Old: &meta, New: &meta}
+}
+
+// fileHookPayload describes the task or project at path as it is now
+func fileHookPayload(path string) (HookPayload, error) {
+	note, err := noteIdentity(path)
+	if err != nil {
+		return HookPayload{}, err
+	}
+
+	switch noteKind(note) {
+	case "task":
+		task, err := parseTaskFile(path)
+		if err != nil {
+			return HookPayload{}, err
+		}
+		return taskHookPayload(path, TaskFrontmatter{ID: note.ID, Title: note.Title, Tags: note.Tags, TaskMetadata: task.TaskMetadata}), nil
+	case "project":
+		project, err := parseProjectFile(path)
+		if err != nil {
+			return HookPayload{}, err
+		}
+		return projectHookPayload(path, ProjectFrontmatter{ID: note.ID, Title: note.Title, Tags: note.Tags, ProjectMetadata: project.ProjectMetadata}), nil
+	}
+	return HookPayload{Kind: "note", ID: note.ID, Title: note.Title, Tags: note.Tags, Path: path}, nil
+}
+
+// updateAction names an update for hooks: "done" when it finishes the task
+// or project, else "update"
+func updateAction(oldStatus, newStatus string) string {
+	if newStatus != oldStatus && (newStatus == "done" || newStatus == "completed") {
+		return "done"
+	}
+	return "update"
+}
+
+// findHooks returns the hooks for an event as commands to run
+func findHooks(config Config, event string) [][]string {
+	var hooks [][]string
+
+	if dir := hooksDir(); dir != "" {
+		candidates := []string{filepath.Join(dir, event)}
+		if entries, err := os.ReadDir(filepath.Join(dir, event+".d")); err == nil {
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				candidates = append(candidates, filepath.Join(dir, event+".d", name))
+			}
+		}
+
+		for _, path := range candidates {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			hooks = append(hooks, []string{path})
+		}
+	}
+
+	if command := strings.TrimSpace(config.TOMLConfig.Hooks[event]); command != "" {
+		hooks = append(hooks, []string{"sh", "-c", command})
+	}
+
+	return hooks
+}
+
+// runHooks runs the hooks for event, stopping at the first that fails
+func runHooks(config Config, event string, payload HookPayload) error {
+	if os.Getenv(hookEnvVar) != "" {
+		return nil
+	}
+
+	hooks := findHooks(config, event)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload.Event = event
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook input: %w", err)
+	}
+
+	for _, hook := range hooks {
+		cmd := exec.Command(hook[0], hook[1:]...)
+		cmd.Dir = filepath.Dir(payload.Path)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(),
+			hookEnvVar+"="+event,
+			"NOTES_CLI_EVENT="+event,
+			"NOTES_CLI_KIND="+payload.Kind,
+			"NOTES_CLI_PATH="+payload.Path,
+		)
+
+		if err := cmd.Run(); err != nil {
+			name := "[hooks] " + event
+			if len(hook) == 1 {
+				name, _ = filepath.Rel(hooksDir(), hook[0])
+			}
+			return fmt.Errorf("hook %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// runPreHooks runs the pre- hooks for an action. An error means a hook
+// vetoed the change.
+func runPreHooks(config Config, action string, payload HookPayload) error {
+	if err := runHooks(config, "pre-"+action, payload); err != nil {
+		return invalidf("change stopped by %v", err)
+	}
+	return nil
+}
+
+// runPostHooks runs the post- hooks for an action. The change has already
+// been made, so failures are only reported.
+func runPostHooks(config Config, action string, payload HookPayload) {
+	if err := runHooks(config, "post-"+action, payload); err != nil {
+		fmt.Printf("%s %v (the change was made)\n", warning("!"), err)
+	}
+}
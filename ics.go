@@ -0,0 +1,521 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type ICSOptions struct {
+	Filters   TaskFilters
+	Tasks     bool
+	Projects  bool
+	Component string // todo, event, or both
+	Output    string // file to write; stdout when empty
+}
+
+// icsWriter builds an iCalendar document with CRLF line endings and lines
+// folded at 75 octets (RFC 5545 section 3.1)
+type icsWriter struct {
+	b strings.Builder
+}
+
+func (w *icsWriter) line(name, value string) {
+	w.raw(name + ":" + value)
+}
+
+// raw writes a complete content line, folding it as needed
+func (w *icsWriter) raw(line string) {
+	for len(line) > 75 {
+		cut := 75
+		// Don't split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.b.WriteString(line[:cut] + "\r\n")
+		line = " " + line[cut:]
+	}
+	w.b.WriteString(line + "\r\n")
+}
+
+func (w *icsWriter) text(name, value string) {
+	w.line(name, icsEscape(value))
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// icsDate converts a YYYY-MM-DD date to the iCalendar DATE form
+func icsDate(date string) (string, bool) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", false
+	}
+	return t.Format("20060102"), true
+}
+
+func icsNextDay(date string) string {
+	t, _ := time.Parse("2006-01-02", date)
+	return t.AddDate(0, 0, 1).Format("20060102")
+}
+
+func icsTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsPriority maps p1-p3 to the iCalendar scale, where 1 is highest
+func icsPriority(p string) string {
+	switch p {
+	case "p1":
+		return "1"
+	case "p2":
+		return "5"
+	case "p3":
+		return "9"
+	}
+	return ""
+}
+
+func icsTaskStatus(status string) string {
+	switch status {
+	case "done":
+		return "COMPLETED"
+	case "dropped":
+		return "CANCELLED"
+	case "delegated":
+		return "IN-PROCESS"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icsStatusProperty keeps a task status that STATUS can't express (paused
+// is NEEDS-ACTION there), so it survives a round trip through a server
+const icsStatusProperty = "X-NOTES-CLI-STATUS"
+
+// icsStatusExtension returns the value for icsStatusProperty, or "" when
+// STATUS alone reads back as status
+func icsStatusExtension(status string) string {
+	if status == "" || taskStatusFromICS(icsTaskStatus(status)) == status {
+		return ""
+	}
+	return status
+}
+
+func icsProjectStatus(status string) string {
+	switch status {
+	case "completed":
+		return "COMPLETED"
+	case "cancelled":
+		return "CANCELLED"
+	case "active":
+		return "IN-PROCESS"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icsItem is a task or project reduced to what the calendar needs
+type icsItem struct {
+	ID          string
+	Kind        string
+	Number      int // task_id or project_id
+	Title       string
+	Tags        []string
+	Priority    string
+	DueDate     string
+	StartDate   string
+	Status      string // iCalendar VTODO status
+	StatusExt   string // see icsStatusProperty
+	Completed   string // completion date, for done tasks
+	Description string
+	Path        string
+	ModTime     time.Time
+}
+
+// uid identifies an item across exports: its Denote identifier, or for a
+// file without one, its task or project number, or failing that its path
+func (item icsItem) uid() string {
+	switch {
+	case item.ID != "":
+		return item.ID
+	case item.Number > 0:
+		return fmt.Sprintf("%s-%d", item.Kind, item.Number)
+	default:
+		return fmt.Sprintf("%s-%x", item.Kind, sha1.Sum([]byte(item.Path)))
+	}
+}
+
+func (w *icsWriter) todo(item icsItem) {
+	w.line("BEGIN", "VTODO")
+	w.line("UID", item.uid()+"@notes-cli")
+	w.line("DTSTAMP", icsTimestamp(item.ModTime))
+	w.line("LAST-MODIFIED", icsTimestamp(item.ModTime))
+	w.text("SUMMARY", item.Title)
+	if start, ok := icsDate(item.StartDate); ok {
+		w.line("DTSTART;VALUE=DATE", start)
+	}
+	if due, ok := icsDate(item.DueDate); ok {
+		w.line("DUE;VALUE=DATE", due)
+	}
+	if p := icsPriority(item.Priority); p != "" {
+		w.line("PRIORITY", p)
+	}
+	w.line("STATUS", item.Status)
+	if item.StatusExt != "" {
+		w.line(icsStatusProperty, item.StatusExt)
+	}
+	if item.Status == "COMPLETED" {
+		// Tasks done before completed_date existed use the last modification
+		completed := item.ModTime
+		if t, err := time.ParseInLocation("2006-01-02", item.Completed, time.Local); err == nil {
+			completed = t
+		}
+		w.line("COMPLETED", icsTimestamp(completed))
+	}
+	w.item(item)
+	w.line("END", "VTODO")
+}
+
+// event renders an all-day event spanning the start date (if any) to the due date
+func (w *icsWriter) event(item icsItem) {
+	first, last := item.StartDate, item.DueDate
+	if _, ok := icsDate(first); !ok {
+		first = last
+	}
+	if _, ok := icsDate(last); !ok || last < first {
+		last = first
+	}
+	start, ok := icsDate(first)
+	if !ok {
+		return
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", item.uid()+"-event@notes-cli")
+	w.line("DTSTAMP", icsTimestamp(item.ModTime))
+	w.line("LAST-MODIFIED", icsTimestamp(item.ModTime))
+	w.text("SUMMARY", item.Title)
+	w.line("DTSTART;VALUE=DATE", start)
+	w.line("DTEND;VALUE=DATE", icsNextDay(last))
+	w.line("TRANSP", "TRANSPARENT")
+	if p := icsPriority(item.Priority); p != "" {
+		w.line("PRIORITY", p)
+	}
+	if item.Status == "CANCELLED" {
+		w.line("STATUS", "CANCELLED")
+	} else {
+		w.line("STATUS", "CONFIRMED")
+	}
+	w.item(item)
+	w.line("END", "VEVENT")
+}
+
+// item writes the properties shared by todos and events
+func (w *icsWriter) item(item icsItem) {
+	var categories []string
+	for _, tag := range item.Tags {
+		if tag != "task" && tag != "project" {
+			categories = append(categories, icsEscape(tag))
+		}
+	}
+	if len(categories) > 0 {
+		w.line("CATEGORIES", strings.Join(categories, ","))
+	}
+	if item.Description != "" {
+		w.text("DESCRIPTION", item.Description)
+	}
+	w.line("URL", "file://"+strings.ReplaceAll(item.Path, " ", "%20"))
+	w.text("X-NOTES-CLI-KIND", item.Kind)
+}
+
+func (w *icsWriter) beginCalendar() {
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//notes-cli//EN")
+	w.line("CALSCALE", "GREGORIAN")
+}
+
+func taskICSItem(task *TaskInfo) icsItem {
+	var details []string
+	if task.TaskID > 0 {
+		details = append(details, fmt.Sprintf("Task #%d", task.TaskID))
+	}
+	if task.Project != "" {
+		details = append(details, "Project: "+task.Project)
+	}
+	if task.Area != "" {
+		details = append(details, "Area: "+task.Area)
+	}
+	if task.Assignee != "" {
+		details = append(details, "Assignee: "+task.Assignee)
+	}
+
+	return icsItem{
+		ID:          task.Note.ID,
+		Kind:        "task",
+		Number:      task.TaskID,
+		Title:       task.Note.Title,
+		Tags:        task.Note.Tags,
+		Priority:    task.Priority,
+		DueDate:     task.DueDate,
+		StartDate:   task.StartDate,
+		Status:      icsTaskStatus(task.Status),
+		StatusExt:   icsStatusExtension(task.Status),
+		Completed:   task.CompletedDate,
+		Description: strings.Join(details, "\n"),
+		Path:        task.Path,
+		ModTime:     task.ModTime,
+	}
+}
+
+func buildICS(config Config, opts ICSOptions) (string, int, error) {
+	var items []icsItem
+
+	if opts.Tasks {
+		tasks, err := findTasks(config, opts.Filters)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, task := range tasks {
+			if task.DueDate == "" && task.StartDate == "" {
+				continue
+			}
+			items = append(items, taskICSItem(&task))
+		}
+	}
+
+	projectFilters, anyProjects := icsProjectFilters(opts.Filters)
+	if opts.Projects && anyProjects {
+		projects, err := findProjects(config, projectFilters)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, project := range projects {
+			if project.DueDate == "" && project.StartDate == "" {
+				continue
+			}
+			// Projects only have an area to filter on
+			if opts.Filters.Area != "" && project.Area != opts.Filters.Area {
+				continue
+			}
+			var details []string
+			if project.ProjectID > 0 {
+				details = append(details, fmt.Sprintf("Project #%d", project.ProjectID))
+			}
+			if project.Area != "" {
+				details = append(details, "Area: "+project.Area)
+			}
+			items = append(items, icsItem{
+				ID:          project.Note.ID,
+				Kind:        "project",
+				Number:      project.ProjectID,
+				Title:       project.Note.Title,
+				Tags:        project.Note.Tags,
+				Priority:    project.Priority,
+				DueDate:     project.DueDate,
+				StartDate:   project.StartDate,
+				Status:      icsProjectStatus(project.Status),
+				Description: strings.Join(details, "\n"),
+				Path:        project.Path,
+				ModTime:     project.ModTime,
+			})
+		}
+	}
+
+	w := &icsWriter{}
+	w.beginCalendar()
+	w.text("X-WR-CALNAME", "notes-cli")
+	for _, item := range items {
+		if opts.Component == "todo" || opts.Component == "both" {
+			w.todo(item)
+		}
+		if opts.Component == "event" || opts.Component == "both" {
+			w.event(item)
+		}
+	}
+	w.line("END", "VCALENDAR")
+
+	return w.b.String(), len(items), nil
+}
+
+// icsProjectFilters applies the task status filter (open tasks, unless -all
+// or -status says otherwise) to projects. It returns false when no project
+// status matches, as for delegated tasks.
+func icsProjectFilters(filters TaskFilters) (ProjectFilters, bool) {
+	if filters.All || filters.Status == "" {
+		return ProjectFilters{All: true, SortBy: "due"}, true
+	}
+	status, ok := map[string]string{
+		"open":    "active",
+		"paused":  "paused",
+		"done":    "completed",
+		"dropped": "cancelled",
+	}[filters.Status]
+	return ProjectFilters{Status: status, SortBy: "due"}, ok
+}
+
+// parseICSInclude reads -include: a comma separated list of tasks, projects
+// and both
+func parseICSInclude(include string) (tasks, projects bool, err error) {
+	for _, part := range strings.Split(include, ",") {
+		switch strings.TrimSpace(part) {
+		case "tasks":
+			tasks = true
+		case "projects":
+			projects = true
+		case "both":
+			tasks, projects = true, true
+		default:
+			return false, false, fmt.Errorf("invalid -include %q (use tasks, projects, or both)", part)
+		}
+	}
+	return tasks, projects, nil
+}
+
+func exportICS(config Config, opts ICSOptions) error {
+	if opts.Component != "todo" && opts.Component != "event" && opts.Component != "both" {
+		return fmt.Errorf("invalid component: %s (must be todo, event, or both)", opts.Component)
+	}
+
+	calendar, n, err := buildICS(config, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == "" {
+		fmt.Print(calendar)
+		return nil
+	}
+
+	if err := os.WriteFile(opts.Output, []byte(calendar), 0644); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", success("✓"), count(n, pluralize(n, "item", "items")), opts.Output)
+
+	return nil
+}
+
+// icsProperty is a parsed content line such as DUE;VALUE=DATE:20250101
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsComponent is a component such as VTODO with its own properties;
+// nested components (VALARM) are skipped
+type icsComponent struct {
+	Name  string
+	Props []icsProperty
+}
+
+func (c icsComponent) get(name string) (icsProperty, bool) {
+	for _, prop := range c.Props {
+		if prop.Name == name {
+			return prop, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+func (c icsComponent) text(name string) string {
+	prop, _ := c.get(name)
+	return icsUnescape(prop.Value)
+}
+
+// unfoldICS splits a calendar into logical lines, joining folded ones
+func unfoldICS(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func parseICSLine(line string) icsProperty {
+	// The value starts at the first colon outside a quoted parameter
+	inQuotes := false
+	split := len(line)
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			split = i
+			break
+		}
+	}
+
+	prop := icsProperty{Params: make(map[string]string)}
+	if split < len(line) {
+		prop.Value = line[split+1:]
+	}
+	parts := strings.Split(line[:split], ";")
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop
+}
+
+// parseICSComponents returns the components of the given type in a calendar
+func parseICSComponents(data, name string) []icsComponent {
+	var components []icsComponent
+	var current *icsComponent
+	depth := 0
+
+	for _, line := range unfoldICS(data) {
+		prop := parseICSLine(line)
+		switch {
+		case prop.Name == "BEGIN" && current == nil && strings.EqualFold(prop.Value, name):
+			current = &icsComponent{Name: name}
+		case prop.Name == "BEGIN" && current != nil:
+			depth++
+		case prop.Name == "END" && current != nil && depth > 0:
+			depth--
+		case prop.Name == "END" && current != nil:
+			components = append(components, *current)
+			current = nil
+		case current != nil && depth == 0:
+			current.Props = append(current.Props, prop)
+		}
+	}
+
+	return components
+}
+
+func icsUnescape(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
+
+// icsValueDate returns the YYYY-MM-DD date of a DATE or DATE-TIME value
+func icsValueDate(value string) string {
+	if len(value) < 8 {
+		return ""
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultJournalTitleFormat = "Monday 2 January 2006"
+
+// defaultJournalTasksTemplate renders the section added by 'journal -tasks'
+const defaultJournalTasksTemplate = `
+## Tasks for {{ .Date }}
+{{ if .Overdue }}
+### Overdue
+{{ range .Overdue }}- [ ] {{ if .TaskID }}#{{ .TaskID }} {{ end }}[{{ .Title }}](denote:{{ .ID }}){{ if .Priority }} {{ .Priority }}{{ end }} (due {{ .DueDate }})
+{{ end }}{{ end }}{{ if .Due }}
+### Due
+{{ range .Due }}- [ ] {{ if .TaskID }}#{{ .TaskID }} {{ end }}[{{ .Title }}](denote:{{ .ID }}){{ if .Priority }} {{ .Priority }}{{ end }}
+{{ end }}{{ end }}{{ if .Completed }}
+### Completed
+{{ range .Completed }}- [x] {{ if .TaskID }}#{{ .TaskID }} {{ end }}[{{ .Title }}](denote:{{ .ID }})
+{{ end }}{{ end }}{{ if not (or .Overdue .Due .Completed) }}
+Nothing due.
+{{ end }}`
+
+// JournalTask is the view of a task available to the journal template
+type JournalTask struct {
+	ID       string
+	TaskID   int
+	Title    string
+	Priority string
+	DueDate  string
+	Project  string
+	Area     string
+}
+
+type JournalTasks struct {
+	Date      string
+	Overdue   []JournalTask
+	Due       []JournalTask
+	Completed []JournalTask
+}
+
+// journal finds or creates the journal note for a day and opens it
+func journal(config Config, dateArg string, withTasks bool, noEdit bool) error {
+	day := time.Now()
+	if dateArg != "" {
+		dateStr, err := parseDate(dateArg)
+		if err != nil {
+			return err
+		}
+		day, _ = time.ParseInLocation("2006-01-02", dateStr, time.Now().Location())
+	}
+
+	notePath, err := findJournalNote(config, day)
+	if err != nil {
+		return err
+	}
+
+	created := false
+	if notePath == "" {
+		titleFormat := config.TOMLConfig.Journal.TitleFormat
+		if titleFormat == "" {
+			titleFormat = defaultJournalTitleFormat
+		}
+
+		// Keep the journal date in the identifier
+		note := Note{
+			ID:    generateDenoteIDOn(day),
+			Title: day.Format(titleFormat),
+			Tags:  []string{"journal"},
+		}
+
+		notePath, err = writeNewNote(config.NotesDir, note, "")
+		if err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if withTasks {
+		section, err := renderJournalTasks(config, day)
+		if err != nil {
+			return err
+		}
+		if err := writeJournalTasks(notePath, section); err != nil {
+			return err
+		}
+	}
+
+	if !noEdit {
+		if err := openInEditor(notePath); err != nil {
+			return err
+		}
+	}
+
+	action := "update"
+	if created {
+		action = "create"
+	}
+	gitAutoCommit(config, fmt.Sprintf("%s: %s", fileLabel(notePath), action), notePath)
+
+	if created {
+		fmt.Printf("%s Journal created: %s\n", success("✓"), bold(day.Format("2006-01-02")))
+	} else {
+		fmt.Printf("%s Journal: %s\n", success("✓"), bold(day.Format("2006-01-02")))
+	}
+	if withTasks {
+		fmt.Println("  Updated tasks section")
+	}
+	fmt.Printf("  Location: %s\n", notePath)
+
+	return nil
+}
+
+// findJournalNote returns the path of the journal note for a day, or an
+// empty string if there isn't one yet
+func findJournalNote(config Config, day time.Time) (string, error) {
+	files, err := globNotes(filepath.Join(config.NotesDir, "*.md"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list files: %w", err)
+	}
+
+	prefix := day.Format("20060102") + "T"
+	for _, file := range files {
+		note, err := noteIdentity(file)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(note.ID, prefix) && hasTag(note.Tags, "journal") {
+			return file, nil
+		}
+	}
+
+	return "", nil
+}
+
+// renderJournalTasks renders the tasks due, overdue and completed on a day
+func renderJournalTasks(config Config, day time.Time) (string, error) {
+	tasks, err := findTasks(config, TaskFilters{All: true, SortBy: "priority"})
+	if err != nil {
+		return "", err
+	}
+
+	dayStr := day.Format("2006-01-02")
+	data := JournalTasks{Date: dayStr}
+
+	for _, task := range tasks {
+		view := JournalTask{
+			ID:       task.Note.ID,
+			TaskID:   task.TaskID,
+			Title:    task.Note.Title,
+			Priority: task.Priority,
+			DueDate:  task.DueDate,
+			Project:  task.Project,
+			Area:     task.Area,
+		}
+
+		switch task.Status {
+		case "done":
+			if task.completedOn() == dayStr {
+				data.Completed = append(data.Completed, view)
+			}
+		case "dropped":
+		default:
+			if task.DueDate == dayStr {
+				data.Due = append(data.Due, view)
+			} else if task.DueDate != "" && task.DueDate < dayStr {
+				data.Overdue = append(data.Overdue, view)
+			}
+		}
+	}
+
+	tmplText := config.TOMLConfig.Journal.TasksTemplate
+	if tmplText == "" {
+		tmplText = defaultJournalTasksTemplate
+	}
+
+	tmpl, err := template.New("journal").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("invalid journal tasks template: %w", err)
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to render journal tasks: %w", err)
+	}
+
+	return result.String(), nil
+}
+
+// The tasks section sits between these markers so running 'journal -tasks'
+// again replaces it instead of adding another
+const (
+	journalTasksStart = "<!-- notes-cli:tasks -->"
+	journalTasksEnd   = "<!-- /notes-cli:tasks -->"
+)
+
+// writeJournalTasks replaces the note's tasks section, or appends one
+func writeJournalTasks(path string, section string) error {
+	section = journalTasksStart + "\n" + strings.Trim(section, "\n") + "\n" + journalTasksEnd + "\n"
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	text := string(content)
+	start := strings.Index(text, journalTasksStart)
+	end := strings.Index(text, journalTasksEnd)
+	if start < 0 || end < start {
+		return appendToFile(path, section)
+	}
+
+	end += len(journalTasksEnd)
+	if end < len(text) && text[end] == '\n' {
+		end++
+	}
+	text = text[:start] + section + text[end:]
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// appendToFile appends text to a file as a new paragraph
+func appendToFile(path string, text string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	existing := strings.TrimRight(string(content), "\n")
+	if existing != "" {
+		existing += "\n\n"
+	}
+	text = strings.TrimLeft(text, "\n")
+
+	if err := os.WriteFile(path, []byte(existing+text), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Denote links reference other files by identifier:
+//
+//	[[denote:20250704T151739]]             (optionally [[denote:ID][description]])
+//	[Some title](denote:20250704T151739)
+var denoteLinkPattern = regexp.MustCompile(`\[\[denote:(\d{8}T\d{6})(?:\]\[[^\]]*)?\]\]|\]\(denote:(\d{8}T\d{6})\)`)
+
+// LinkedFile is a note, task or project on either end of a link
+type LinkedFile struct {
+	ID    string
+	Title string
+	Kind  string
+	Path  string
+}
+
+// extractDenoteLinks returns the unique identifiers linked from content, in order
+func extractDenoteLinks(content string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+
+	for _, match := range denoteLinkPattern.FindAllStringSubmatch(content, -1) {
+		id := match[1]
+		if id == "" {
+			id = match[2]
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// noteIdentity reads a file's Denote identity, using the frontmatter first
+// and the filename for anything the frontmatter leaves out
+func noteIdentity(path string) (*Note, error) {
+	note, err := parseNoteFile(path)
+	fromName, nameErr := parseFilename(filepath.Base(path))
+	if err != nil {
+		if nameErr != nil {
+			return nil, err
+		}
+		return fromName, nil
+	}
+
+	if nameErr == nil {
+		if note.ID == "" {
+			note.ID = fromName.ID
+		}
+		if note.Title == "" {
+			note.Title = fromName.Title
+		}
+		if note.Signature == "" {
+			note.Signature = fromName.Signature
+		}
+	}
+
+	return note, nil
+}
+
+// noteKind classifies a file as a task, project or plain note by its tags
+func noteKind(note *Note) string {
+	switch {
+	case hasTag(note.Tags, "task"):
+		return "task"
+	case hasTag(note.Tags, "project"):
+		return "project"
+	default:
+		return "note"
+	}
+}
+
+// buildIDIndex maps every Denote identifier in the vault to its file
+func buildIDIndex(config Config) (map[string]LinkedFile, error) {
+	files, err := vaultFiles(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	index := make(map[string]LinkedFile)
+	for _, file := range files {
+		note, err := noteIdentity(file)
+		if err != nil || note.ID == "" {
+			continue
+		}
+		if _, exists := index[note.ID]; exists {
+			continue
+		}
+		index[note.ID] = LinkedFile{
+			ID:    note.ID,
+			Title: note.Title,
+			Kind:  noteKind(note),
+			Path:  file,
+		}
+	}
+
+	return index, nil
+}
+
+// findFileByDenoteID finds the file with the given Denote identifier
+func findFileByDenoteID(config Config, id string) (*LinkedFile, error) {
+	index, err := buildIDIndex(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if linked, ok := index[id]; ok {
+		return &linked, nil
+	}
+
+	return nil, fmt.Errorf("no file found with ID %s", id)
+}
+
+// resolveNoteRef resolves a reference which can be:
+// - A Denote identifier (20250704T151739)
+// - A task ID with a prefix (#3 or task:3)
+// - A note index from the last listing, or a task ID
+// - A filename
+func resolveNoteRef(config Config, ref string) (string, error) {
+	if isDenoteIdentifier(ref) {
+		linked, err := findFileByDenoteID(config, ref)
+		if err != nil {
+			return "", err
+		}
+		return linked.Path, nil
+	}
+
+	if id, ok := taskRef(ref); ok {
+		task, err := findTaskByID(config, id)
+		if err != nil {
+			return "", err
+		}
+		return task.Path, nil
+	}
+
+	// A bare number could be either, so it has to be unambiguous
+	if index, err := strconv.Atoi(ref); err == nil {
+		task, taskErr := findTaskByID(config, index)
+		noteInfo, noteErr := getNoteByIndex(config, index)
+		switch {
+		case taskErr == nil && noteErr == nil && task.Path != noteInfo.Path:
+			return "", fmt.Errorf("%d is both task #%d and note %d of the last 'note list'; use task:%d (or '#%d') for the task, or the note's Denote ID", index, index, index, index, index)
+		case taskErr == nil:
+			return task.Path, nil
+		case noteErr == nil:
+			return noteInfo.Path, nil
+		}
+		return "", fmt.Errorf("no task #%d and no note %d in the last 'note list'", index, index)
+	}
+
+	path := findNoteFile(config, ref)
+	if path == "" {
+		return "", fmt.Errorf("file not found: %s", ref)
+	}
+	return path, nil
+}
+
+// linkNotes appends a Markdown Denote link to the target at the end of the source file
+func linkNotes(config Config, fromRef, toRef string) error {
+	fromPath, err := resolveNoteRef(config, fromRef)
+	if err != nil {
+		return err
+	}
+
+	toPath, err := resolveNoteRef(config, toRef)
+	if err != nil {
+		return err
+	}
+
+	if fromPath == toPath {
+		return fmt.Errorf("cannot link a note to itself")
+	}
+
+	target, err := noteIdentity(toPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse target: %w", err)
+	}
+	if target.ID == "" {
+		return fmt.Errorf("target has no Denote ID: %s", toPath)
+	}
+
+	content, err := os.ReadFile(fromPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Don't add a second link to the same target
+	for _, id := range extractDenoteLinks(string(content)) {
+		if id == target.ID {
+			fmt.Printf("Already linked to: %s\n", target.Title)
+			return nil
+		}
+	}
+
+	title := target.Title
+	if title == "" {
+		title = target.ID
+	}
+	link := fmt.Sprintf("[%s](denote:%s)", title, target.ID)
+
+	text := string(content)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += "\n" + link + "\n"
+
+	if err := os.WriteFile(fromPath, []byte(text), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	gitAutoCommit(config, fmt.Sprintf("%s: link to %q", fileLabel(fromPath), title), fromPath)
+
+	fmt.Printf("%s Linked to: %s\n", success("✓"), bold(title))
+	fmt.Printf("  %s %s\n", dim("Location:"), filename(fromPath))
+	return nil
+}
+
+// findOutgoingLinks resolves the links in a file to the files they point at.
+// Links to identifiers that no longer exist are returned with an empty Path.
+func findOutgoingLinks(config Config, path string) ([]LinkedFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := extractDenoteLinks(string(content))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	index, err := buildIDIndex(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []LinkedFile
+	for _, id := range ids {
+		if linked, ok := index[id]; ok {
+			links = append(links, linked)
+		} else {
+			links = append(links, LinkedFile{ID: id})
+		}
+	}
+
+	return links, nil
+}
+
+// findBacklinks returns every file that links to the given identifier
+func findBacklinks(config Config, id string) ([]LinkedFile, error) {
+	files, err := vaultFiles(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var backlinks []LinkedFile
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		linked := false
+		for _, linkID := range extractDenoteLinks(string(content)) {
+			if linkID == id {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			continue
+		}
+
+		note, err := noteIdentity(file)
+		if err != nil || note.ID == id {
+			continue
+		}
+
+		backlinks = append(backlinks, LinkedFile{
+			ID:    note.ID,
+			Title: note.Title,
+			Kind:  noteKind(note),
+			Path:  file,
+		})
+	}
+
+	// Group by kind, then title
+	sort.Slice(backlinks, func(i, j int) bool {
+		if backlinks[i].Kind != backlinks[j].Kind {
+			return backlinks[i].Kind < backlinks[j].Kind
+		}
+		return strings.ToLower(backlinks[i].Title) < strings.ToLower(backlinks[j].Title)
+	})
+
+	return backlinks, nil
+}
+
+func listBacklinks(config Config, ref string) error {
+	path, err := resolveNoteRef(config, ref)
+	if err != nil {
+		return err
+	}
+
+	target, err := noteIdentity(path)
+	if err != nil {
+		return fmt.Errorf("failed to parse note: %w", err)
+	}
+	if target.ID == "" {
+		return fmt.Errorf("file has no Denote ID: %s", path)
+	}
+
+	backlinks, err := findBacklinks(config, target.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(backlinks) == 0 {
+		fmt.Printf("No backlinks to: %s\n", target.Title)
+		return nil
+	}
+
+	fmt.Printf("%s %s:\n\n", bold("Backlinks to"), target.Title)
+	for _, link := range backlinks {
+		fmt.Printf("  %s %s %s\n", linkKind(link.Kind), link.Title, date("("+link.ID+")"))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// taskRef parses an explicit task reference, #3 or task:3
+func taskRef(ref string) (int, bool) {
+	rest, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		rest, ok = strings.CutPrefix(ref, "task:")
+	}
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(rest)
+	return id, err == nil
+}
+
+func linkKind(kind string) string {
+	switch kind {
+	case "task":
+		return cyan("[task]   ")
+	case "project":
+		return blue("[project]")
+	default:
+		return magenta("[note]   ")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -25,61 +24,18 @@ type IndexCache struct {
 	Created time.Time  `json:"created"`
 }
 
-func listNotes(config Config, tagFilter string) error {
-	// Get all markdown files in notes directory
-	files, err := filepath.Glob(filepath.Join(config.NotesDir, "*.md"))
-	if err != nil {
-		return fmt.Errorf("failed to list files: %w", err)
-	}
-	
-	var notes []NoteInfo
-	
-	// Parse each file
-	for _, file := range files {
-		info, err := os.Stat(file)
-		if err != nil {
-			continue
-		}
-		
-		note, err := parseNoteFile(file)
-		if err != nil {
-			// Try parsing from filename if frontmatter fails
-			note, err = parseFilename(filepath.Base(file))
-			if err != nil {
-				continue
-			}
-		}
-		
-		// Apply tag filter if specified
-		if tagFilter != "" {
-			hasTag := false
-			for _, tag := range note.Tags {
-				if tag == tagFilter {
-					hasTag = true
-					break
-				}
-			}
-			if !hasTag {
-				continue
-			}
-		}
-		
-		notes = append(notes, NoteInfo{
-			Filename: filepath.Base(file),
-			Path:     file,
-			Note:     note,
-			ModTime:  info.ModTime(),
-		})
-	}
-	
-	// Sort by modification time (most recent first)
-	sort.Slice(notes, func(i, j int) bool {
-		return notes[i].ModTime.After(notes[j].ModTime)
-	})
+type NoteFilters struct {
+	Tag     string
+	SortBy  string
+	Reverse bool
+}
+
+func listNotes(config Config, filters NoteFilters) error {
+	tagFilter := filters.Tag
 	
-	// Assign indices
-	for i := range notes {
-		notes[i].Index = i + 1
+	notes, err := findNotes(config, filters)
+	if err != nil {
+		return err
 	}
 	
 	// Save index cache
@@ -119,12 +75,17 @@ func listNotes(config Config, tagFilter string) error {
 			dateStr = info.Note.ID
 		} else {
 			// Try to extract from filename if ID is empty
-			if matches := regexp.MustCompile(`^(\d{8}T\d{6})`).FindStringSubmatch(info.Filename); len(matches) > 1 {
-				id := matches[1]
-				dateStr = fmt.Sprintf("%s-%s-%s", id[0:4], id[4:6], id[6:8])
+			if fromName, err := parseFilename(info.Filename); err == nil {
+				dateStr = formatDateFromID(fromName.ID)
 			}
 		}
 		
+		// Show signature ahead of the title
+		sigStr := ""
+		if info.Note.Signature != "" {
+			sigStr = gray("=" + info.Note.Signature) + " "
+		}
+		
 		// Format tags with colors
 		tagStr := ""
 		if len(info.Note.Tags) > 0 {
@@ -135,8 +96,9 @@ func listNotes(config Config, tagFilter string) error {
 			tagStr = " [" + strings.Join(coloredTags, ", ") + "]"
 		}
 		
-		fmt.Printf("  %s %s%s %s\n", 
+		fmt.Printf("  %s %s%s%s %s\n", 
 			index(info.Index), 
+			sigStr,
 			info.Note.Title, 
 			tagStr,
 			date("(" + dateStr + ")"))
@@ -146,6 +108,127 @@ func listNotes(config Config, tagFilter string) error {
 	return nil
 }
 
+// printNotesJSON prints the notes 'note list' would show, as JSON
+func printNotesJSON(config Config, filters NoteFilters) error {
+	notes, err := findNotes(config, filters)
+	if err != nil {
+		return err
+	}
+	
+	result := []NoteJSON{}
+	for _, n := range notes {
+		result = append(result, noteJSON(n.Note, n.Path, n.ModTime))
+	}
+	return printJSON(result)
+}
+
+// findNotes returns the notes matching filters, sorted and indexed
+func findNotes(config Config, filters NoteFilters) ([]NoteInfo, error) {
+	// Get all markdown files in notes directory
+	files, err := globNotes(filepath.Join(config.NotesDir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	
+	var notes []NoteInfo
+	
+	// Parse each file
+	for _, file := range files {
+		info, err := cachedNoteFile(file)
+		if err != nil {
+			continue
+		}
+		
+		// Apply tag filter if specified
+		if filters.Tag != "" && !hasTag(info.Note.Tags, filters.Tag) {
+			continue
+		}
+		
+		notes = append(notes, *info)
+	}
+	
+	// Sort notes
+	sortNotes(notes, filters.SortBy, filters.Reverse)
+	
+	// Assign indices
+	for i := range notes {
+		notes[i].Index = i + 1
+	}
+	
+	return notes, nil
+}
+
+// parseNoteInfo reads a note file, falling back to its filename when the
+// frontmatter can't be parsed
+func parseNoteInfo(file string) (*NoteInfo, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	
+	note, err := parseNoteFile(file)
+	if err != nil {
+		// Try parsing from filename if frontmatter fails
+		note, err = parseFilename(filepath.Base(file))
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	// Fall back to the filename for the signature
+	if note.Signature == "" {
+		note.Signature = signatureFromFilename(file)
+	}
+	
+	return &NoteInfo{
+		Filename: filepath.Base(file),
+		Path:     file,
+		Note:     note,
+		ModTime:  info.ModTime(),
+	}, nil
+}
+
+func sortNotes(notes []NoteInfo, sortBy string, reverse bool) {
+	switch sortBy {
+	case "signature":
+		sort.SliceStable(notes, func(i, j int) bool {
+			// Luhmann-style sequence, notes without a signature last
+			c := compareSignatures(notes[i].Note.Signature, notes[j].Note.Signature)
+			if c == 0 {
+				c = strings.Compare(notes[i].Note.ID, notes[j].Note.ID)
+			}
+			if reverse {
+				return c > 0
+			}
+			return c < 0
+		})
+	case "created":
+		sort.Slice(notes, func(i, j int) bool {
+			result := notes[i].Note.ID > notes[j].Note.ID
+			if reverse {
+				return !result
+			}
+			return result
+		})
+	case "title":
+		sort.Slice(notes, func(i, j int) bool {
+			result := strings.ToLower(notes[i].Note.Title) < strings.ToLower(notes[j].Note.Title)
+			if reverse {
+				return !result
+			}
+			return result
+		})
+	default: // modified
+		sort.Slice(notes, func(i, j int) bool {
+			result := notes[i].ModTime.After(notes[j].ModTime)
+			if reverse {
+				return !result
+			}
+			return result
+		})
+	}
+}
+
 func saveIndexCache(config Config, notes []NoteInfo) error {
 	cache := IndexCache{
 		Notes:   notes,
@@ -186,7 +269,7 @@ func getNoteByIndex(config Config, index int) (*NoteInfo, error) {
 	cache, err := loadIndexCache(config)
 	if err != nil {
 		// Regenerate list
-		files, err := filepath.Glob(filepath.Join(config.NotesDir, "*.md"))
+		files, err := globNotes(filepath.Join(config.NotesDir, "*.md"))
 		if err != nil {
 			return nil, fmt.Errorf("failed to list files: %w", err)
 		}
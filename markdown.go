@@ -0,0 +1,266 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A small Markdown renderer for the HTML report. It covers what notes
+// usually contain: headings, paragraphs, lists (with task checkboxes),
+// block quotes, fenced code, rules, emphasis, code spans and links,
+// including both forms of denote: links.
+
+// denoteResolver returns the URL and title of the page for a Denote ID
+type denoteResolver func(id string) (href, title string, ok bool)
+
+var (
+	mdHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListPattern     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdRulePattern     = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdCheckboxPattern = regexp.MustCompile(`^\[([ xX])\]\s+`)
+
+	mdCodeSpanPattern    = regexp.MustCompile("`([^`]+)`")
+	mdDenoteOrgPattern   = regexp.MustCompile(`\[\[denote:(\d{8}T\d{6})(?:\]\[([^\]]*))?\]\]`)
+	mdLinkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldPattern        = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalicPattern      = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	mdAutolinkPattern    = regexp.MustCompile(`(^|[\s(])(https?://[^\s<)]+)`)
+	mdPlaceholderPattern = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// renderMarkdown converts Markdown to HTML
+func renderMarkdown(text string, resolve denoteResolver) string {
+	r := &mdRenderer{resolve: resolve}
+	r.blocks(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+	return r.out.String()
+}
+
+type mdRenderer struct {
+	out     strings.Builder
+	resolve denoteResolver
+}
+
+func (r *mdRenderer) blocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			if lang != "" {
+				r.out.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			} else {
+				r.out.WriteString("<pre><code>")
+			}
+			r.out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			r.out.WriteString("</code></pre>\n")
+
+		case mdHeadingPattern.MatchString(trimmed):
+			m := mdHeadingPattern.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(m[1]))
+			r.out.WriteString("<h" + level + ">" + r.inline(m[2]) + "</h" + level + ">\n")
+			i++
+
+		case mdRulePattern.MatchString(line):
+			r.out.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+				i++
+			}
+			r.out.WriteString("<blockquote>\n")
+			r.blocks(quote)
+			r.out.WriteString("</blockquote>\n")
+
+		case mdListPattern.MatchString(line):
+			i = r.list(lines, i)
+
+		default:
+			var para []string
+			for i < len(lines) {
+				l := lines[i]
+				t := strings.TrimSpace(l)
+				if t == "" || strings.HasPrefix(t, "```") || strings.HasPrefix(t, ">") ||
+					mdHeadingPattern.MatchString(t) || mdListPattern.MatchString(l) || mdRulePattern.MatchString(l) {
+					break
+				}
+				para = append(para, t)
+				i++
+			}
+			r.out.WriteString("<p>" + r.inline(strings.Join(para, "\n")) + "</p>\n")
+		}
+	}
+}
+
+// list renders the list starting at lines[start] and returns the index of
+// the first line after it. Items indented further start a nested list.
+func (r *mdRenderer) list(lines []string, start int) int {
+	m := mdListPattern.FindStringSubmatch(lines[start])
+	indent := len(m[1])
+	ordered := !strings.ContainsAny(m[2], "-*+")
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	r.out.WriteString("<" + tag + ">\n")
+
+	i := start
+	for i < len(lines) {
+		m := mdListPattern.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) < indent {
+			break
+		}
+		if len(m[1]) == indent && strings.ContainsAny(m[2], "-*+") == ordered {
+			// A different kind of list starts here
+			break
+		}
+		if len(m[1]) > indent {
+			i = r.list(lines, i)
+			continue
+		}
+
+		item := m[3]
+		i++
+		// Lazy continuation lines belong to the item
+		for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !mdListPattern.MatchString(lines[i]) &&
+			strings.HasPrefix(lines[i], " ") {
+			item += "\n" + strings.TrimSpace(lines[i])
+			i++
+		}
+
+		r.out.WriteString("<li>")
+		if c := mdCheckboxPattern.FindStringSubmatch(item); c != nil {
+			checked := ""
+			if c[1] != " " {
+				checked = " checked"
+			}
+			r.out.WriteString(`<input type="checkbox" disabled` + checked + `> `)
+			item = item[len(c[0]):]
+		}
+		r.out.WriteString(r.inline(item))
+
+		// A nested list directly under this item
+		if i < len(lines) {
+			if next := mdListPattern.FindStringSubmatch(lines[i]); next != nil && len(next[1]) > indent {
+				r.out.WriteString("\n")
+				i = r.list(lines, i)
+			}
+		}
+		r.out.WriteString("</li>\n")
+
+		// Skip a single blank line between items of the same list
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			if next := mdListPattern.FindStringSubmatch(lines[i+1]); next != nil && len(next[1]) >= indent {
+				i++
+			}
+		}
+	}
+
+	r.out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// inline renders emphasis, code spans and links. Finished HTML is swapped
+// out for placeholders so later patterns don't match inside it.
+func (r *mdRenderer) inline(text string) string {
+	var saved []string
+	hold := func(s string) string {
+		saved = append(saved, s)
+		return "\x00" + strconv.Itoa(len(saved)-1) + "\x00"
+	}
+
+	text = mdCodeSpanPattern.ReplaceAllStringFunc(text, func(s string) string {
+		return hold("<code>" + html.EscapeString(mdCodeSpanPattern.FindStringSubmatch(s)[1]) + "</code>")
+	})
+
+	text = mdDenoteOrgPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdDenoteOrgPattern.FindStringSubmatch(s)
+		return hold(r.denoteLink(m[1], m[2]))
+	})
+
+	text = mdLinkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdLinkPattern.FindStringSubmatch(s)
+		label, target := m[1], m[2]
+		if strings.HasPrefix(target, "denote:") {
+			return hold(r.denoteLink(strings.TrimPrefix(target, "denote:"), label))
+		}
+		if !safeLinkTarget(target) {
+			return hold(html.EscapeString(label))
+		}
+		return hold(`<a href="` + html.EscapeString(target) + `">` + html.EscapeString(label) + `</a>`)
+	})
+
+	text = mdAutolinkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdAutolinkPattern.FindStringSubmatch(s)
+		return m[1] + hold(`<a href="`+html.EscapeString(m[2])+`">`+html.EscapeString(m[2])+`</a>`)
+	})
+
+	text = html.EscapeString(text)
+
+	text = mdBoldPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdBoldPattern.FindStringSubmatch(s)
+		return "<strong>" + m[1] + m[2] + "</strong>"
+	})
+	text = mdItalicPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdItalicPattern.FindStringSubmatch(s)
+		return "<em>" + m[1] + m[2] + "</em>"
+	})
+
+	return mdPlaceholderPattern.ReplaceAllStringFunc(text, func(s string) string {
+		n, _ := strconv.Atoi(mdPlaceholderPattern.FindStringSubmatch(s)[1])
+		return saved[n]
+	})
+}
+
+// safeLinkTarget reports whether a link can be published: relative links and
+// http, https and mailto URLs. Anything else (javascript:, data:) could run
+// in the reader's browser.
+func safeLinkTarget(target string) bool {
+	u, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// denoteLink links to the page for id, or shows the label if the file
+// isn't part of the report
+func (r *mdRenderer) denoteLink(id, label string) string {
+	href, title, ok := "", "", false
+	if r.resolve != nil {
+		href, title, ok = r.resolve(id)
+	}
+	if label == "" {
+		label = title
+	}
+	if label == "" {
+		label = id
+	}
+	if !ok {
+		return `<span class="broken-link" title="` + id + `">` + html.EscapeString(label) + `</span>`
+	}
+	return `<a href="` + html.EscapeString(href) + `">` + html.EscapeString(label) + `</a>`
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// orgTodoKeywords declares the task statuses as org TODO keywords; the ones
+// after the bar count as done for the agenda
+const orgTodoKeywords = "#+TODO: TODO WAIT DELEGATED | DONE DROPPED"
+
+func orgKeyword(status string) string {
+	switch status {
+	case "done":
+		return "DONE"
+	case "dropped":
+		return "DROPPED"
+	case "paused":
+		return "WAIT"
+	case "delegated":
+		return "DELEGATED"
+	}
+	return "TODO"
+}
+
+func orgPriority(p string) string {
+	switch p {
+	case "p1":
+		return "[#A]"
+	case "p2":
+		return "[#B]"
+	case "p3":
+		return "[#C]"
+	}
+	return ""
+}
+
+// orgTimestamp formats a YYYY-MM-DD date as an active org timestamp
+func orgTimestamp(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return t.Format("<2006-01-02 Mon>")
+}
+
+// orgTags formats tags as :tag1:tag2:. Org tags can't contain '-'.
+func orgTags(tags []string) string {
+	var names []string
+	for _, tag := range tags {
+		if tag == "task" {
+			continue
+		}
+		names = append(names, strings.ReplaceAll(tag, "-", "_"))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return ":" + strings.Join(names, ":") + ":"
+}
+
+func writeOrgTask(buf *bytes.Buffer, task TaskInfo) {
+	heading := []string{"**", orgKeyword(task.Status)}
+	if p := orgPriority(task.Priority); p != "" {
+		heading = append(heading, p)
+	}
+	heading = append(heading, task.Note.Title)
+	if tags := orgTags(task.Note.Tags); tags != "" {
+		heading = append(heading, tags)
+	}
+	buf.WriteString(strings.Join(heading, " ") + "\n")
+
+	var planning []string
+	if ts := orgTimestamp(task.DueDate); ts != "" {
+		planning = append(planning, "DEADLINE: "+ts)
+	}
+	if ts := orgTimestamp(task.StartDate); ts != "" {
+		planning = append(planning, "SCHEDULED: "+ts)
+	}
+	if len(planning) > 0 {
+		buf.WriteString("   " + strings.Join(planning, " ") + "\n")
+	}
+
+	buf.WriteString("   :PROPERTIES:\n")
+	if task.TaskID > 0 {
+		fmt.Fprintf(buf, "   :TASK_ID:  %d\n", task.TaskID)
+	}
+	if task.Project != "" {
+		fmt.Fprintf(buf, "   :PROJECT:  %s\n", task.Project)
+	}
+	if task.Area != "" {
+		fmt.Fprintf(buf, "   :AREA:     %s\n", task.Area)
+	}
+	path := task.Path
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	fmt.Fprintf(buf, "   :FILE:     [[file:%s]]\n", path)
+	buf.WriteString("   :END:\n")
+}
+
+// buildOrg renders tasks as an org file with one top-level heading per
+// project or area
+func buildOrg(tasks []TaskInfo, groupBy string) string {
+	none := "No " + groupBy
+	groups := make(map[string][]TaskInfo)
+	for _, task := range tasks {
+		key := task.Project
+		if groupBy == "area" {
+			key = task.Area
+		}
+		if key == "" {
+			key = none
+		}
+		groups[key] = append(groups[key], task)
+	}
+
+	var names []string
+	for name := range groups {
+		if name != none {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	if _, ok := groups[none]; ok {
+		names = append(names, none)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("#+TITLE: Tasks\n")
+	buf.WriteString(orgTodoKeywords + "\n")
+	buf.WriteString("#+STARTUP: overview\n")
+
+	for _, name := range names {
+		buf.WriteString("\n* " + name + "\n")
+		for _, task := range groups[name] {
+			writeOrgTask(&buf, task)
+		}
+	}
+
+	return buf.String()
+}
+
+func exportOrg(config Config, filters TaskFilters, groupBy, output string) error {
+	if groupBy != "project" && groupBy != "area" {
+		return fmt.Errorf("invalid -group %q (use project or area)", groupBy)
+	}
+
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+
+	org := buildOrg(tasks, groupBy)
+
+	if output == "" {
+		fmt.Print(org)
+		return nil
+	}
+
+	if err := os.WriteFile(output, []byte(org), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", success("✓"), count(len(tasks), pluralize(len(tasks), "task", "tasks")), output)
+	fmt.Printf("→ Add it to org-agenda-files to see the tasks in the agenda\n")
+
+	return nil
+}
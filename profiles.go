@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ProfileConfig is one vault ([profiles.<name>] in config.toml). Unset
+// fields fall back to the top-level settings.
+type ProfileConfig struct {
+	NotesDir    string           `toml:"notes_dir"`
+	TaskDir     string           `toml:"task_dir"`
+	SoonHorizon int              `toml:"soon_horizon"`
+	Vocabulary  VocabularyConfig `toml:"vocabulary"`
+}
+
+// VocabularyConfig limits the areas and tags tasks and projects can use.
+// An empty list allows anything.
+type VocabularyConfig struct {
+	Areas []string `toml:"areas"`
+	Tags  []string `toml:"tags"`
+}
+
+// selectedProfile is set by the global -profile flag
+var selectedProfile string
+
+// allProfiles is set by the global -all-profiles flag
+var allProfiles bool
+
+// profileName returns the profile to use: -profile, then $NOTES_PROFILE,
+// then default_profile. Empty means the top-level settings.
+func profileName(tomlConfig *TOMLConfig) string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	if name := os.Getenv("NOTES_PROFILE"); name != "" {
+		return name
+	}
+	return tomlConfig.DefaultProfile
+}
+
+func profileNames(tomlConfig *TOMLConfig) []string {
+	var names []string
+	for name := range tomlConfig.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile overrides the top-level settings with a profile's
+func applyProfile(tomlConfig *TOMLConfig, name string) error {
+	profile, ok := tomlConfig.Profiles[name]
+	if !ok {
+		if len(tomlConfig.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q (no profiles in config.toml)", name)
+		}
+		return fmt.Errorf("unknown profile %q (profiles: %s)", name, strings.Join(profileNames(tomlConfig), ", "))
+	}
+
+	// Settings from a .notes-cli.toml beat the profile's
+	override := func(key string, set bool) bool {
+		if !set || (tomlConfig.localFile != "" && tomlConfig.sources[key] == tomlConfig.localFile) {
+			return false
+		}
+		tomlConfig.sources[key] = "profile " + name
+		if source := tomlConfig.sources["profiles."+name]; source != "" {
+			tomlConfig.sources[key] += " (" + source + ")"
+		}
+		return true
+	}
+
+	if override("notes_dir", true) {
+		tomlConfig.NotesDir = profile.NotesDir
+	}
+	if override("task_dir", true) {
+		tomlConfig.TaskDir = profile.TaskDir
+	}
+	if override("soon_horizon", profile.SoonHorizon > 0) {
+		tomlConfig.SoonHorizon = profile.SoonHorizon
+	}
+	if override("vocabulary.areas", len(profile.Vocabulary.Areas) > 0) {
+		tomlConfig.Vocabulary.Areas = profile.Vocabulary.Areas
+	}
+	if override("vocabulary.tags", len(profile.Vocabulary.Tags) > 0) {
+		tomlConfig.Vocabulary.Tags = profile.Vocabulary.Tags
+	}
+	return nil
+}
+
+// checkVocabulary rejects areas and tags outside the configured vocabulary
+func checkVocabulary(config Config, areaName string, tags []string) error {
+	vocab := config.TOMLConfig.Vocabulary
+
+	if areaName != "" && len(vocab.Areas) > 0 && !containsTag(vocab.Areas, areaName) {
+		return invalidf("area %q is not in the vocabulary (areas: %s)", areaName, strings.Join(vocab.Areas, ", "))
+	}
+
+	if len(vocab.Tags) > 0 {
+		for _, tag := range tags {
+			if tag == "task" || tag == "project" {
+				continue
+			}
+			if !containsTag(vocab.Tags, tag) {
+				return invalidf("tag %q is not in the vocabulary (tags: %s)", tag, strings.Join(vocab.Tags, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// runAllProfiles runs a command once per profile and tags each line of
+// output with the profile's name. JSON output is merged into one array
+// with a "vault" field on each result.
+func runAllProfiles(config Config, args []string, jsonOutput bool) error {
+	names := profileNames(config.TOMLConfig)
+	if len(names) == 0 {
+		return fmt.Errorf("no profiles in config.toml")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find notes-cli executable: %w", err)
+	}
+
+	var merged []map[string]interface{}
+	var failed []string
+	for _, name := range names {
+		cmd := exec.Command(self, args...)
+		cmd.Env = append(os.Environ(), "NOTES_PROFILE="+name)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			failed = append(failed, name)
+		}
+
+		if jsonOutput {
+			var results []map[string]interface{}
+			if err := json.Unmarshal(out, &results); err != nil {
+				if len(bytes.TrimSpace(out)) > 0 {
+					fmt.Fprintf(os.Stderr, "[%s] %s\n", name, bytes.TrimSpace(out))
+				}
+				continue
+			}
+			for _, result := range results {
+				result["vault"] = name
+			}
+			merged = append(merged, results...)
+			continue
+		}
+
+		tag := info("[" + name + "]")
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			if scanner.Text() == "" {
+				fmt.Println()
+				continue
+			}
+			fmt.Printf("%s %s\n", tag, scanner.Text())
+		}
+	}
+
+	if jsonOutput {
+		if merged == nil {
+			merged = []map[string]interface{}{}
+		}
+		data, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed in %s %s", pluralize(len(failed), "profile", "profiles"), strings.Join(failed, ", "))
+	}
+	return nil
+}
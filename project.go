@@ -10,12 +10,12 @@ import (
 )
 
 type ProjectMetadata struct {
-	ProjectID int    `yaml:"project_id,omitempty"`
-	Status    string `yaml:"status,omitempty"`    // active, completed, paused, cancelled
-	Priority  string `yaml:"priority,omitempty"`  // p1, p2, p3
-	StartDate string `yaml:"start_date,omitempty"`
-	DueDate   string `yaml:"due_date,omitempty"`
-	Area      string `yaml:"area,omitempty"` // work, personal, etc.
+	ProjectID int    `yaml:"project_id,omitempty" json:"project_id,omitempty"`
+	Status    string `yaml:"status,omitempty" json:"status,omitempty"`     // active, completed, paused, cancelled
+	Priority  string `yaml:"priority,omitempty" json:"priority,omitempty"` // p1, p2, p3
+	StartDate string `yaml:"start_date,omitempty" json:"start_date,omitempty"`
+	DueDate   string `yaml:"due_date,omitempty" json:"due_date,omitempty"`
+	Area      string `yaml:"area,omitempty" json:"area,omitempty"` // work, personal, etc.
 }
 
 type Project struct {
@@ -28,7 +28,8 @@ func (p Project) Frontmatter() string {
 id: "{{ .ID }}"{{ if .ProjectID }}
 project_id: {{ .ProjectID }}{{ end }}
 title: "{{ .Title }}"
-date: {{ .Date }}
+date: {{ .Date }}{{ if .Signature }}
+signature: "{{ .Signature }}"{{ end }}
 tags:{{ range .Tags }}
   - {{ . }}{{ end }}{{ if .Status }}
 status: {{ .Status }}{{ end }}{{ if .Priority }}
@@ -45,6 +46,7 @@ area: "{{ .Area }}"{{ end }}
 	tpl.Execute(&result, map[string]interface{}{
 		"ID":        p.ID,
 		"ProjectID": p.ProjectID,
+		"Signature": p.Signature,
 		"Title":     p.Title,
 		"Date":      formatDateFromID(p.ID),
 		"Tags":      p.Tags,
@@ -58,23 +60,33 @@ area: "{{ .Area }}"{{ end }}
 	return result.String()
 }
 
-func createProject(config Config, title string, meta ProjectMetadata, extraTags []string, noEdit bool) error {
+func createProject(config Config, title string, meta ProjectMetadata, extraTags []string, body string, noEdit bool) error {
+	project, filepath, err := writeProject(config, title, meta, extraTags, body, noEdit)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("✓ Project #%d created: %s\n", project.ProjectID, project.Title)
+	if project.DueDate != "" {
+		fmt.Printf("  Status: %s | Due: %s\n", project.Status, getDueDateDisplay(project.DueDate))
+	} else {
+		fmt.Printf("  Status: %s\n", project.Status)
+	}
+	fmt.Printf("  Location: %s\n", filepath)
+	fmt.Printf("→ Run 'notes-cli project-tasks \"%s\"' to add tasks\n", project.Title)
+	return nil
+}
+
+// writeProject creates a project file and returns the project and its path
+// without printing anything
+func writeProject(config Config, title string, meta ProjectMetadata, extraTags []string, body string, noEdit bool) (*Project, string, error) {
 	// Set defaults
 	if meta.Status == "" {
 		meta.Status = "active"
 	}
 	
-	// Assign project ID if not provided
-	if meta.ProjectID == 0 {
-		counter, err := getIDCounter(config)
-		if err != nil {
-			return fmt.Errorf("failed to get ID counter: %w", err)
-		}
-		projectID, err := counter.NextProject()
-		if err != nil {
-			return fmt.Errorf("failed to get next project ID: %w", err)
-		}
-		meta.ProjectID = projectID
+	if err := checkVocabulary(config, meta.Area, extraTags); err != nil {
+		return nil, "", err
 	}
 	
 	// Build tags - always include "project"
@@ -97,19 +109,39 @@ func createProject(config Config, title string, meta ProjectMetadata, extraTags
 	
 	// Check if file already exists
 	if _, err := os.Stat(filepath); err == nil {
-		return fmt.Errorf("file already exists: %s", filepath)
+		return nil, "", invalidf("file already exists: %s", filepath)
 	}
 	
+	payload := projectHookPayload(filepath, ProjectFrontmatter{ID: project.ID, Title: project.Title, Tags: project.Tags, ProjectMetadata: meta})
+	payload.Old = nil
+	if err := runPreHooks(config, "create", payload); err != nil {
+		return nil, "", err
+	}
+	
+	// Assign project ID if not provided, once nothing can stop the create,
+	// so a vetoed or invalid project doesn't use up an ID
+	if project.ProjectID == 0 {
+		counter, err := getIDCounter(config)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get ID counter: %w", err)
+		}
+		projectID, err := counter.NextProject()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get next project ID: %w", err)
+		}
+		project.ProjectID = projectID
+	}
+
 	// Create file with frontmatter
 	file, err := os.Create(filepath)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return nil, "", fmt.Errorf("failed to create file: %w", err)
 	}
 	
-	_, err = file.WriteString(project.Frontmatter())
+	_, err = file.WriteString(project.Frontmatter() + body)
 	file.Close()
 	if err != nil {
-		return fmt.Errorf("failed to write frontmatter: %w", err)
+		return nil, "", fmt.Errorf("failed to write frontmatter: %w", err)
 	}
 	
 	// Open in editor unless --no-edit flag is set
@@ -125,17 +157,21 @@ func createProject(config Config, title string, meta ProjectMetadata, extraTags
 		cmd.Stderr = os.Stderr
 		
 		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to open editor: %w", err)
+			return nil, "", fmt.Errorf("failed to open editor: %w", err)
 		}
 	}
-	
-	fmt.Printf("✓ Project #%d created: %s\n", project.ProjectID, project.Title)
-	if project.DueDate != "" {
-		fmt.Printf("  Status: %s | Due: %s\n", project.Status, getDueDateDisplay(project.DueDate))
-	} else {
-		fmt.Printf("  Status: %s\n", project.Status)
+
+	gitAutoCommit(config, fmt.Sprintf("project #%d: create %q", project.ProjectID, project.Title), filepath)
+
+	if edited, err := fileHookPayload(filepath); err == nil {
+		payload = edited
+		payload.Old = nil
 	}
-	fmt.Printf("  Location: %s\n", filepath)
-	fmt.Printf("→ Run 'notes-cli project-tasks \"%s\"' to add tasks\n", project.Title)
-	return nil
-}
\ No newline at end of file
+	runPostHooks(config, "create", payload)
+	
+	return &project, filepath, nil
+}
+
+func isValidProjectStatus(s string) bool {
+	return s == "active" || s == "completed" || s == "paused" || s == "cancelled"
+}
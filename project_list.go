@@ -18,10 +18,11 @@ type ProjectInfo struct {
 }
 
 type ProjectFrontmatter struct {
-	ID    string   `yaml:"id"`
-	Title string   `yaml:"title"`
-	Date  string   `yaml:"date"`
-	Tags  []string `yaml:"tags"`
+	ID        string   `yaml:"id"`
+	Signature string   `yaml:"signature"`
+	Title     string   `yaml:"title"`
+	Date      string   `yaml:"date"`
+	Tags      []string `yaml:"tags"`
 	ProjectMetadata `yaml:",inline"`
 }
 
@@ -32,40 +33,79 @@ func listProjects(config Config, filters ProjectFilters) error {
 		filters.Status = "active"
 	}
 	
+	projects, err := findProjects(config, filters)
+	if err != nil {
+		return err
+	}
+	
+	if projects == nil {
+		fmt.Println("No projects found")
+		return nil
+	}
+	
+	// Display results
+	displayProjects(projects, filters)
+	
+	// Save index cache for project operations
+	saveProjectIndexCache(config, projects)
+	
+	return nil
+}
+
+// printProjectsJSON prints the projects 'project list' would show, as JSON
+func printProjectsJSON(config Config, filters ProjectFilters) error {
+	if !filters.All && filters.Status == "" {
+		filters.Status = "active"
+	}
+	
+	projects, err := findProjects(config, filters)
+	if err != nil {
+		return err
+	}
+	
+	result := []ProjectJSON{}
+	for i := range projects {
+		result = append(result, projectJSON(&projects[i]))
+	}
+	return printJSON(result)
+}
+
+// findProjects returns the projects matching filters, sorted and indexed.
+// It returns nil when there are no project files at all.
+func findProjects(config Config, filters ProjectFilters) ([]ProjectInfo, error) {
 	// Get all markdown files with __project in the filename from both directories
 	var files []string
 	
 	// Check notes directory
 	pattern := filepath.Join(config.NotesDir, "*__project*.md")
-	notesFiles, err := filepath.Glob(pattern)
+	notesFiles, err := globNotes(pattern)
 	if err != nil {
-		return fmt.Errorf("failed to list project files in notes dir: %w", err)
+		return nil, fmt.Errorf("failed to list project files in notes dir: %w", err)
 	}
 	files = append(files, notesFiles...)
 	
 	// Check task directory if it's different
 	if config.TaskDir != config.NotesDir {
 		pattern = filepath.Join(config.TaskDir, "*__project*.md")
-		taskFiles, err := filepath.Glob(pattern)
+		taskFiles, err := globNotes(pattern)
 		if err != nil {
-			return fmt.Errorf("failed to list project files in task dir: %w", err)
+			return nil, fmt.Errorf("failed to list project files in task dir: %w", err)
 		}
 		files = append(files, taskFiles...)
 	}
 	
 	if len(files) == 0 {
-		fmt.Println("No projects found")
-		return nil
+		return nil, nil
 	}
 	
 	// Parse each file to get project details
-	var projects []ProjectInfo
+	projects := []ProjectInfo{}
 	for _, file := range files {
 		if file == "" {
 			continue
 		}
 		
-		projectInfo, err := parseProjectFile(file)
+		projectInfo, err := cachedProjectFile(file)
 		if err != nil {
 			continue
 		}
@@ -96,13 +136,7 @@ func listProjects(config Config, filters ProjectFilters) error {
 		projects[i].Index = i + 1
 	}
 	
-	// Display results
-	displayProjects(projects, filters)
-	
-	// Save index cache for project operations
-	saveProjectIndexCache(config, projects)
-	
-	return nil
+	return projects, nil
 }
 
 func parseProjectFile(filePath string) (*ProjectInfo, error) {
@@ -153,9 +187,10 @@ func parseProjectFile(filePath string) (*ProjectInfo, error) {
 			Filename: filepath.Base(filePath),
 			Path:     filePath,
 			Note: &Note{
-				ID:    fm.ID,
-				Title: fm.Title,
-				Tags:  fm.Tags,
+				ID:        fm.ID,
+				Signature: fm.Signature,
+				Title:     fm.Title,
+				Tags:      fm.Tags,
 			},
 			ModTime: info.ModTime(),
 		},
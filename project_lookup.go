@@ -13,14 +13,14 @@ func findProjectByID(config Config, projectID int) (*ProjectInfo, error) {
 	
 	// Get project files from both directories
 	notesPattern := filepath.Join(config.NotesDir, "*__project*.md")
-	notesFiles, err := filepath.Glob(notesPattern)
+	notesFiles, err := globNotes(notesPattern)
 	if err == nil {
 		files = append(files, notesFiles...)
 	}
 	
 	if config.TaskDir != config.NotesDir {
 		taskPattern := filepath.Join(config.TaskDir, "*__project*.md")
-		taskFiles, err := filepath.Glob(taskPattern)
+		taskFiles, err := globNotes(taskPattern)
 		if err == nil {
 			files = append(files, taskFiles...)
 		}
@@ -73,11 +73,11 @@ func resolveProjectArg(config Config, arg string) (string, error) {
 		var projects []ProjectInfo
 		var files []string
 		
-		notesFiles, _ := filepath.Glob(filepath.Join(config.NotesDir, "*__project*.md"))
+		notesFiles, _ := globNotes(filepath.Join(config.NotesDir, "*__project*.md"))
 		files = append(files, notesFiles...)
 		
 		if config.TaskDir != config.NotesDir {
-			taskFiles, _ := filepath.Glob(filepath.Join(config.TaskDir, "*__project*.md"))
+			taskFiles, _ := globNotes(filepath.Join(config.TaskDir, "*__project*.md"))
 			files = append(files, taskFiles...)
 		}
 		
@@ -102,11 +102,11 @@ func resolveProjectArg(config Config, arg string) (string, error) {
 	// Otherwise treat as project name - find the file
 	var files []string
 	
-	notesFiles, _ := filepath.Glob(filepath.Join(config.NotesDir, "*__project*.md"))
+	notesFiles, _ := globNotes(filepath.Join(config.NotesDir, "*__project*.md"))
 	files = append(files, notesFiles...)
 	
 	if config.TaskDir != config.NotesDir {
-		taskFiles, _ := filepath.Glob(filepath.Join(config.TaskDir, "*__project*.md"))
+		taskFiles, _ := globNotes(filepath.Join(config.TaskDir, "*__project*.md"))
 		files = append(files, taskFiles...)
 	}
 	
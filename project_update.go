@@ -17,10 +17,35 @@ func updateProject(config Config, arg string, updates ProjectMetadata, tagUpdate
 		return err
 	}
 	
+	newPath, err := applyProjectUpdate(config, notePath, updates, tagUpdates)
+	if err != nil {
+		return err
+	}
+	
+	// Show success message
+	fmt.Printf("%s Project updated successfully\n", success("✓"))
+	fmt.Printf("  %s %s\n", dim("Location:"), filename(notePath))
+	if newPath != notePath {
+		fmt.Printf("Renamed to: %s\n", newPath)
+	}
+	
+	return nil
+}
+
+// applyProjectUpdate updates the project at notePath without printing
+// anything and returns its (possibly new) path
+func applyProjectUpdate(config Config, notePath string, updates ProjectMetadata, tagUpdates string) (string, error) {
+	if updates.Status != "" && !isValidProjectStatus(updates.Status) {
+		return "", invalidf("invalid status: %s (must be active, completed, paused, or cancelled)", updates.Status)
+	}
+	if updates.Priority != "" && !isValidPriority(updates.Priority) {
+		return "", invalidf("invalid priority: %s (must be p1, p2, or p3)", updates.Priority)
+	}
+	
 	// Read the file
 	content, err := os.ReadFile(notePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 	
 	// Parse frontmatter and content
@@ -42,15 +67,17 @@ func updateProject(config Config, arg string, updates ProjectMetadata, tagUpdate
 	}
 	
 	if frontmatterStart == -1 || frontmatterEnd == -1 {
-		return fmt.Errorf("no frontmatter found in file")
+		return "", fmt.Errorf("no frontmatter found in file")
 	}
 	
 	// Parse existing frontmatter
 	yamlContent := strings.Join(lines[frontmatterStart+1:frontmatterEnd], "\n")
 	var fm ProjectFrontmatter
 	if err := yaml.Unmarshal([]byte(yamlContent), &fm); err != nil {
-		return fmt.Errorf("failed to parse frontmatter: %w", err)
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	before := fm
+	before.Tags = append([]string(nil), fm.Tags...)
 	
 	// Apply updates
 	if updates.Status != "" {
@@ -69,22 +96,46 @@ func updateProject(config Config, arg string, updates ProjectMetadata, tagUpdate
 		fm.Area = updates.Area
 	}
 	
+	// Keep a signature that only exists in the filename
+	if fm.Signature == "" {
+		fm.Signature = signatureFromFilename(notePath)
+	}
+	
 	// Apply tag updates
 	if tagUpdates != "" {
 		tagUpdate := parseTagUpdates(tagUpdates)
 		fm.Tags = applyTagUpdates(fm.Tags, tagUpdate)
 	}
 	
+	// Only check the tags being added, so existing ones can still be removed
+	var addedTags []string
+	for _, tag := range fm.Tags {
+		if !containsTag(before.Tags, tag) {
+			addedTags = append(addedTags, tag)
+		}
+	}
+	if err := checkVocabulary(config, updates.Area, addedTags); err != nil {
+		return "", err
+	}
+	
 	// Create updated project for frontmatter generation
 	project := Project{
 		Note: Note{
-			ID:    fm.ID,
-			Title: fm.Title,
-			Tags:  fm.Tags,
+			ID:        fm.ID,
+			Signature: fm.Signature,
+			Title:     fm.Title,
+			Tags:      fm.Tags,
 		},
 		ProjectMetadata: fm.ProjectMetadata,
 	}
 	
+	action := updateAction(before.Status, fm.Status)
+	payload := projectHookPayload(notePath, fm)
+	payload.Old = &before.ProjectMetadata
+	if err := runPreHooks(config, action, payload); err != nil {
+		return "", err
+	}
+	
 	// Generate new frontmatter
 	newFrontmatter := project.Frontmatter()
 	
@@ -100,26 +151,27 @@ func updateProject(config Config, arg string, updates ProjectMetadata, tagUpdate
 	
 	// Write back to file
 	if err := os.WriteFile(notePath, []byte(strings.Join(newContent, "\n")), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return "", fmt.Errorf("failed to write file: %w", err)
 	}
 	
-	// Show success message
-	fmt.Printf("%s Project updated successfully\n", success("✓"))
-	fmt.Printf("  %s %s\n", dim("Location:"), filename(notePath))
-	
 	// If title or tags changed, might need to rename file
 	oldFilename := filepath.Base(notePath)
 	newFilename := project.Filename()
+	newPath := notePath
 	
 	if oldFilename != newFilename {
-		newPath := filepath.Join(filepath.Dir(notePath), newFilename)
+		newPath = filepath.Join(filepath.Dir(notePath), newFilename)
 		if err := os.Rename(notePath, newPath); err != nil {
-			return fmt.Errorf("failed to rename file: %w", err)
+			return "", fmt.Errorf("failed to rename file: %w", err)
 		}
-		fmt.Printf("Renamed to: %s\n", newPath)
+		payload.Path = newPath
 	}
+
+	gitAutoCommit(config, fmt.Sprintf("project #%d: %s", fm.ProjectID, describeProjectChanges(before, fm)), notePath, newPath)
+
+	runPostHooks(config, action, payload)
 	
-	return nil
+	return newPath, nil
 }
 
 // updateProjects updates one or more projects with the same metadata
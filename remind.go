@@ -0,0 +1,543 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultRemindTime is the time of day a due date means
+const defaultRemindTime = "09:00"
+
+// Reminder is one reminder for a task, as notifiers receive it
+type Reminder struct {
+	ID       string    `json:"id"`
+	TaskID   int       `json:"task_id"`
+	Title    string    `json:"title"`
+	Priority string    `json:"priority,omitempty"`
+	DueDate  string    `json:"due_date,omitempty"`
+	Remind   string    `json:"remind,omitempty"`
+	At       time.Time `json:"at"`
+	Message  string    `json:"message"`
+	Path     string    `json:"path"`
+}
+
+// key identifies a reminder in the state file. Moving the due date or
+// changing remind makes a new reminder.
+func (r Reminder) key() string {
+	return r.ID + "@" + r.At.Format(time.RFC3339)
+}
+
+// RemindOptions are the 'remind' flags
+type RemindOptions struct {
+	Daemon    bool
+	Interval  time.Duration // 0 means [remind] interval
+	Notifiers []string      // nil means [remind] notifiers
+	Late      time.Duration // how late a missed reminder is still sent
+	DryRun    bool
+	List      bool
+}
+
+// reminderTimes works out when a task's reminders are due. An empty remind
+// means the due date itself; "none" turns reminders off.
+func reminderTimes(remind, dueDate, atTime string) ([]time.Time, error) {
+	loc := time.Now().Location()
+
+	var due time.Time
+	if dueDate != "" {
+		d, err := time.ParseInLocation("2006-01-02", dueDate, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date %q", dueDate)
+		}
+		due = d
+	}
+
+	remind = strings.TrimSpace(remind)
+	if remind == "none" {
+		return nil, nil
+	}
+	if remind == "" {
+		if due.IsZero() {
+			return nil, nil
+		}
+		remind = atTime
+	}
+
+	var times []time.Time
+	for _, entry := range strings.Split(remind, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		t, err := reminderTime(entry, due, atTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// reminderTime parses one remind entry: an offset from the due date (-1d,
+// -2h, +1d), a time on the due date (09:00), both (-1d 17:00), or a date and
+// time (2025-03-01 14:00)
+func reminderTime(entry string, due time.Time, atTime string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, entry, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", entry, loc); err == nil {
+		due = t
+		entry = ""
+	}
+
+	clock := atTime
+	days := 0
+	var offset time.Duration
+	for _, field := range strings.Fields(entry) {
+		if _, err := time.Parse("15:04", field); err == nil {
+			clock = field
+			continue
+		}
+		d, duration, err := parseRemindOffset(field)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid remind %q (use -1d, -2h, 09:00 or YYYY-MM-DD HH:MM)", entry)
+		}
+		days += d
+		offset += duration
+	}
+	if due.IsZero() {
+		return time.Time{}, fmt.Errorf("remind %q needs a due date", entry)
+	}
+
+	c, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid reminder time %q", clock)
+	}
+	at := time.Date(due.Year(), due.Month(), due.Day(), c.Hour(), c.Minute(), 0, 0, loc)
+	return at.AddDate(0, 0, days).Add(offset), nil
+}
+
+// parseRemindOffset parses -1d, -2w, -3h or -30m as days and a duration.
+// No sign means before the due date, like "-"; "+" means after.
+func parseRemindOffset(s string) (int, time.Duration, error) {
+	sign := -1
+	switch {
+	case strings.HasPrefix(s, "+"):
+		sign = 1
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		s = s[1:]
+	}
+	if len(s) < 2 {
+		return 0, 0, fmt.Errorf("invalid offset")
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("invalid offset")
+	}
+	switch s[len(s)-1] {
+	case 'w':
+		return sign * n * 7, 0, nil
+	case 'd':
+		return sign * n, 0, nil
+	case 'h':
+		return 0, time.Duration(sign*n) * time.Hour, nil
+	case 'm':
+		return 0, time.Duration(sign*n) * time.Minute, nil
+	}
+	return 0, 0, fmt.Errorf("invalid offset")
+}
+
+// checkRemind validates a remind value before it's saved
+func checkRemind(remind string) error {
+	_, err := reminderTimes(remind, time.Now().Format("2006-01-02"), defaultRemindTime)
+	return err
+}
+
+// collectReminders returns the reminders of every unfinished task, soonest
+// first
+func collectReminders(config Config) ([]Reminder, error) {
+	tasks, err := findTasks(config, TaskFilters{All: true, SortBy: "due"})
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []Reminder
+	for _, task := range tasks {
+		if task.Status == "done" || task.Status == "dropped" {
+			continue
+		}
+		times, err := reminderTimes(task.Remind, task.DueDate, config.TOMLConfig.Remind.Time)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s task #%d: %v\n", warning("!"), task.TaskID, err)
+			continue
+		}
+		for _, at := range times {
+			r := Reminder{
+				ID:       task.Note.ID,
+				TaskID:   task.TaskID,
+				Title:    task.Note.Title,
+				Priority: task.Priority,
+				DueDate:  task.DueDate,
+				Remind:   task.Remind,
+				At:       at,
+				Path:     task.Path,
+			}
+			r.Message = reminderMessage(r)
+			reminders = append(reminders, r)
+		}
+	}
+
+	sort.SliceStable(reminders, func(i, j int) bool {
+		return reminders[i].At.Before(reminders[j].At)
+	})
+	return reminders, nil
+}
+
+// reminderMessage says what a reminder is about, e.g. "Buy milk is due
+// tomorrow"
+func reminderMessage(r Reminder) string {
+	if r.DueDate == "" {
+		return r.Title
+	}
+
+	loc := time.Now().Location()
+	due, err := time.ParseInLocation("2006-01-02", r.DueDate, loc)
+	if err != nil {
+		return r.Title
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	days := int(due.Sub(today).Hours() / 24)
+
+	switch {
+	case days == 0:
+		return r.Title + " is due today"
+	case days == 1:
+		return r.Title + " is due tomorrow"
+	case days > 1:
+		return fmt.Sprintf("%s is due in %d days (%s)", r.Title, days, r.DueDate)
+	case days == -1:
+		return r.Title + " was due yesterday"
+	}
+	return fmt.Sprintf("%s is %d days overdue", r.Title, -days)
+}
+
+// Sent reminders
+
+type reminderState struct {
+	Sent map[string]time.Time `json:"sent"` // Reminder.key() -> when it was sent
+}
+
+func reminderStatePath(config Config) string {
+	return filepath.Join(config.TaskDir, ".notes-cli-reminders.json")
+}
+
+func loadReminderState(config Config) (*reminderState, error) {
+	state := &reminderState{Sent: make(map[string]time.Time)}
+
+	data, err := os.ReadFile(reminderStatePath(config))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read reminder state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", reminderStatePath(config), err)
+	}
+	if state.Sent == nil {
+		state.Sent = make(map[string]time.Time)
+	}
+
+	return state, nil
+}
+
+func (s *reminderState) save(config Config) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder state: %w", err)
+	}
+	if err := os.WriteFile(reminderStatePath(config), data, 0644); err != nil {
+		return fmt.Errorf("failed to write reminder state: %w", err)
+	}
+	return nil
+}
+
+// prune forgets reminders sent long enough ago that they can't come round
+// again
+func (s *reminderState) prune(late time.Duration) {
+	cutoff := time.Now().Add(-late - 30*24*time.Hour)
+	for key, sent := range s.Sent {
+		if sent.Before(cutoff) {
+			delete(s.Sent, key)
+		}
+	}
+}
+
+// Notifiers
+
+var notifierNames = []string{"stdout", "notify-send", "webhook", "exec"}
+
+func isNotifier(name string) bool {
+	return containsTag(notifierNames, name)
+}
+
+// Notifier delivers reminders somewhere
+type Notifier interface {
+	Name() string
+	Notify(r Reminder) error
+}
+
+func newNotifier(name string, config Config) (Notifier, error) {
+	settings := config.TOMLConfig.Remind
+	switch name {
+	case "stdout":
+		return stdoutNotifier{}, nil
+	case "notify-send":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil, fmt.Errorf("notify-send not found")
+		}
+		return notifySendNotifier{}, nil
+	case "webhook":
+		if settings.Webhook == "" {
+			return nil, fmt.Errorf("the webhook notifier needs [remind] webhook in config.toml")
+		}
+		return webhookNotifier{url: settings.Webhook, client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "exec":
+		if settings.Exec == "" {
+			return nil, fmt.Errorf("the exec notifier needs [remind] exec in config.toml")
+		}
+		return execNotifier{command: settings.Exec}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier %q (use %s)", name, strings.Join(notifierNames, ", "))
+}
+
+// stdoutNotifier prints reminders, for cron mail or a terminal
+type stdoutNotifier struct{}
+
+func (stdoutNotifier) Name() string { return "stdout" }
+
+func (stdoutNotifier) Notify(r Reminder) error {
+	parts := []string{warning("⏰"), dim(r.At.Format("2006-01-02 15:04")), fmt.Sprintf("#%d", r.TaskID)}
+	if r.Priority != "" {
+		parts = append(parts, priority(r.Priority))
+	}
+	parts = append(parts, r.Message)
+	fmt.Println(strings.Join(parts, " "))
+	return nil
+}
+
+// notifySendNotifier shows a desktop notification
+type notifySendNotifier struct{}
+
+func (notifySendNotifier) Name() string { return "notify-send" }
+
+func (notifySendNotifier) Notify(r Reminder) error {
+	urgency := "normal"
+	if r.Priority == "p1" {
+		urgency = "critical"
+	}
+	body := fmt.Sprintf("%s (task #%d)", r.Message, r.TaskID)
+	return exec.Command("notify-send", "-a", "notes-cli", "-u", urgency, r.Title, body).Run()
+}
+
+// webhookNotifier POSTs the reminder as JSON
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (webhookNotifier) Name() string { return "webhook" }
+
+func (n webhookNotifier) Notify(r Reminder) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", n.url, resp.Status)
+	}
+	return nil
+}
+
+// execNotifier runs a command with the reminder as JSON on stdin
+type execNotifier struct {
+	command string
+}
+
+func (execNotifier) Name() string { return "exec" }
+
+func (n execNotifier) Notify(r Reminder) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+	cmd := exec.Command("sh", "-c", n.command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"NOTES_CLI_TASK_ID="+strconv.Itoa(r.TaskID),
+		"NOTES_CLI_TITLE="+r.Title,
+		"NOTES_CLI_MESSAGE="+r.Message,
+		"NOTES_CLI_PATH="+r.Path,
+	)
+	return cmd.Run()
+}
+
+// The command
+
+func remind(config Config, opts RemindOptions) error {
+	if opts.List {
+		return listReminders(config, opts.Late)
+	}
+
+	names := opts.Notifiers
+	if names == nil {
+		names = config.TOMLConfig.Remind.Notifiers
+	}
+	var notifiers []Notifier
+	for _, name := range names {
+		notifier, err := newNotifier(name, config)
+		if err != nil {
+			return err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if len(notifiers) == 0 {
+		return fmt.Errorf("no notifiers configured")
+	}
+
+	if !opts.Daemon {
+		return sendReminders(config, notifiers, opts)
+	}
+
+	interval := opts.Interval
+	if interval == 0 {
+		interval, _ = time.ParseDuration(config.TOMLConfig.Remind.Interval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "%s Checking reminders every %s (Ctrl-C to stop)\n", info("→"), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := sendReminders(config, notifiers, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", warning("!"), err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendReminders delivers the reminders that have come due and not been sent
+func sendReminders(config Config, notifiers []Notifier, opts RemindOptions) error {
+	state, err := loadReminderState(config)
+	if err != nil {
+		return err
+	}
+	reminders, err := collectReminders(config)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, r := range reminders {
+		if r.At.After(now) {
+			break
+		}
+		if now.Sub(r.At) > opts.Late {
+			continue
+		}
+		if _, sent := state.Sent[r.key()]; sent {
+			continue
+		}
+
+		if opts.DryRun {
+			fmt.Printf("Would remind: #%d %s (%s)\n", r.TaskID, r.Message, r.At.Format("2006-01-02 15:04"))
+			continue
+		}
+
+		delivered := false
+		for _, notifier := range notifiers {
+			if err := notifier.Notify(r); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: task #%d: %v\n", warning("!"), notifier.Name(), r.TaskID, err)
+				continue
+			}
+			delivered = true
+		}
+		// Undelivered reminders are tried again next time
+		if delivered {
+			state.Sent[r.key()] = now
+		}
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	state.prune(opts.Late)
+	return state.save(config)
+}
+
+// listReminders shows reminders still to come and those sent recently
+func listReminders(config Config, late time.Duration) error {
+	state, err := loadReminderState(config)
+	if err != nil {
+		return err
+	}
+	reminders, err := collectReminders(config)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var shown []Reminder
+	for _, r := range reminders {
+		if now.Sub(r.At) <= late {
+			shown = append(shown, r)
+		}
+	}
+	if len(shown) == 0 {
+		fmt.Println("No reminders")
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", bold("Reminders:"))
+	for _, r := range shown {
+		status := ""
+		if _, sent := state.Sent[r.key()]; sent {
+			status = "  " + success("✓ sent")
+		} else if !r.At.After(now) {
+			status = "  " + warning("due now")
+		}
+		when := r.At.Format("Mon 2006-01-02 15:04")
+		fmt.Printf("  %s  #%d %s%s\n", dim(when), r.TaskID, r.Message, status)
+	}
+	fmt.Println()
+	return nil
+}
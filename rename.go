@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -13,10 +12,11 @@ import (
 )
 
 type Frontmatter struct {
-	ID    string   `yaml:"id"`
-	Title string   `yaml:"title"`
-	Date  string   `yaml:"date"`
-	Tags  []string `yaml:"tags"`
+	ID        string   `yaml:"id"`
+	Signature string   `yaml:"signature"`
+	Title     string   `yaml:"title"`
+	Date      string   `yaml:"date"`
+	Tags      []string `yaml:"tags"`
 }
 
 func parseNoteFile(filepath string) (*Note, error) {
@@ -62,34 +62,35 @@ func parseNoteFile(filepath string) (*Note, error) {
 	}
 	
 	return &Note{
-		ID:    fm.ID,
-		Title: fm.Title,
-		Tags:  fm.Tags,
+		ID:        fm.ID,
+		Signature: fm.Signature,
+		Title:     fm.Title,
+		Tags:      fm.Tags,
 	}, nil
 }
 
 func parseFilename(filename string) (*Note, error) {
-	// Remove .md extension
-	name := strings.TrimSuffix(filename, ".md")
-	
-	// Pattern: ID--TITLE__TAGS
-	pattern := `^(\d{8}T\d{6})--([^_]+)(?:__(.+))?$`
-	re := regexp.MustCompile(pattern)
+	// Components can come in any order: ID, ==SIGNATURE, --TITLE, __TAGS
+	components, err := splitFilename(filename)
+	if err != nil {
+		return nil, err
+	}
 	
-	matches := re.FindStringSubmatch(name)
-	if len(matches) < 3 {
+	id := components[componentIdentifier]
+	if !isDenoteIdentifier(id) {
 		return nil, fmt.Errorf("filename does not match denote pattern")
 	}
 	
 	note := &Note{
-		ID:    matches[1],
-		Title: unslugify(matches[2]),
-		Tags:  []string{},
+		ID:        id,
+		Signature: components[componentSignature],
+		Title:     unslugify(components[componentTitle]),
+		Tags:      []string{},
 	}
 	
 	// Parse tags if present
-	if len(matches) > 3 && matches[3] != "" {
-		note.Tags = strings.Split(matches[3], "_")
+	if keywords := components[componentKeywords]; keywords != "" {
+		note.Tags = strings.Split(keywords, "_")
 	}
 	
 	return note, nil
@@ -138,6 +139,11 @@ func renameNote(config Config, filename string) error {
 		return fmt.Errorf("failed to parse note: %w", err)
 	}
 	
+	// Keep a signature that only exists in the filename
+	if note.Signature == "" {
+		note.Signature = signatureFromFilename(oldPath)
+	}
+	
 	// Generate new filename based on frontmatter
 	newFilename := note.Filename()
 	newPath := filepath.Join(filepath.Dir(oldPath), newFilename)
@@ -158,6 +164,9 @@ func renameNote(config Config, filename string) error {
 		return fmt.Errorf("failed to rename file: %w", err)
 	}
 	
+	message := fmt.Sprintf("%s: rename %s → %s", fileLabel(newPath), filepath.Base(oldPath), filepath.Base(newPath))
+	gitAutoCommit(config, message, oldPath, newPath)
+	
 	fmt.Printf("Renamed: %s -> %s\n", oldPath, newPath)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTMLReportOptions controls report html
+type HTMLReportOptions struct {
+	OutDir string
+	Title  string
+}
+
+// The report is a handful of static pages plus one page per task, project
+// and note, linked with relative URLs so the directory can be served from
+// anywhere (or opened straight from disk):
+//
+//	index.html            task board grouped by status
+//	overdue.html          overdue tasks
+//	projects.html         projects with progress
+//	notes.html            notes
+//	tasks/ID.html, projects/ID.html, notes/ID.html
+var boardStatuses = []string{"open", "paused", "delegated", "done", "dropped"}
+
+type reportTask struct {
+	ID       string
+	TaskID   int
+	Title    string
+	Status   string
+	Priority string
+	DueDate  string
+	Start    string
+	Project  string
+	Area     string
+	Assignee string
+	Tags     []string
+	Overdue  bool
+}
+
+type reportProject struct {
+	ID       string
+	Title    string
+	Status   string
+	Priority string
+	DueDate  string
+	Area     string
+	Tags     []string
+	Tasks    []reportTask
+	Done     int
+	Total    int
+	Percent  int
+}
+
+type reportNote struct {
+	ID       string
+	Title    string
+	Date     string
+	Tags     []string
+	Modified string
+}
+
+type reportColumn struct {
+	Status string
+	Tasks  []reportTask
+}
+
+// reportPage is what every page template gets
+type reportPage struct {
+	Site      string
+	Title     string
+	Root      string // base URL: the output directory relative to the page
+	Active    string
+	Generated string
+
+	Columns  []reportColumn
+	Tasks    []reportTask
+	Projects []reportProject
+	Notes    []reportNote
+
+	Task    *reportTask
+	Project *reportProject
+	Note    *reportNote
+	Body    template.HTML
+	Links   []LinkedFile
+}
+
+const reportLayout = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }} · {{ .Site }}</title>
+<base href="{{ .Root }}">
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<header>
+<strong>{{ .Site }}</strong>
+<nav>
+<a href="index.html"{{ if eq .Active "board" }} class="active"{{ end }}>Board</a>
+<a href="overdue.html"{{ if eq .Active "overdue" }} class="active"{{ end }}>Overdue</a>
+<a href="projects.html"{{ if eq .Active "projects" }} class="active"{{ end }}>Projects</a>
+<a href="notes.html"{{ if eq .Active "notes" }} class="active"{{ end }}>Notes</a>
+</nav>
+</header>
+<main>
+{{ template "content" . }}
+</main>
+<footer>Generated {{ .Generated }} by notes-cli</footer>
+</body>
+</html>
+{{ define "card" }}<div class="card{{ if .Overdue }} overdue{{ end }}">
+<a href="tasks/{{ .ID }}.html">#{{ .TaskID }} {{ .Title }}</a>
+<div class="meta">{{ if .Priority }}<span class="{{ .Priority }}">{{ .Priority }}</span> {{ end }}{{ if .DueDate }}due {{ .DueDate }} {{ end }}{{ if .Project }}· {{ .Project }}{{ end }}</div>
+</div>{{ end }}
+{{ define "taskrow" }}<tr{{ if .Overdue }} class="overdue"{{ end }}>
+<td>#{{ .TaskID }}</td>
+<td><a href="tasks/{{ .ID }}.html">{{ .Title }}</a></td>
+<td>{{ .Status }}</td>
+<td>{{ if .Priority }}<span class="{{ .Priority }}">{{ .Priority }}</span>{{ end }}</td>
+<td>{{ .DueDate }}</td>
+<td>{{ .Project }}</td>
+<td>{{ .Area }}</td>
+</tr>{{ end }}
+{{ define "tags" }}{{ range . }}<span class="tag">{{ . }}</span> {{ end }}{{ end }}
+`
+
+const reportBoardPage = `{{ define "content" }}
+<h1>Task board</h1>
+<div class="board">
+{{ range .Columns }}<section class="column">
+<h2>{{ .Status }} <span class="count">{{ len .Tasks }}</span></h2>
+{{ range .Tasks }}{{ template "card" . }}
+{{ end }}</section>
+{{ end }}</div>
+{{ end }}`
+
+const reportTaskTable = `<table>
+<thead><tr><th>ID</th><th>Task</th><th>Status</th><th>Priority</th><th>Due</th><th>Project</th><th>Area</th></tr></thead>
+<tbody>
+{{ range .Tasks }}{{ template "taskrow" . }}
+{{ end }}</tbody>
+</table>`
+
+const reportOverduePage = `{{ define "content" }}
+<h1>Overdue <span class="count">{{ len .Tasks }}</span></h1>
+{{ if .Tasks }}` + reportTaskTable + `{{ else }}<p>Nothing is overdue.</p>{{ end }}
+{{ end }}`
+
+const reportProjectsPage = `{{ define "content" }}
+<h1>Projects</h1>
+<table>
+<thead><tr><th>Project</th><th>Status</th><th>Priority</th><th>Due</th><th>Progress</th></tr></thead>
+<tbody>
+{{ range .Projects }}<tr>
+<td><a href="projects/{{ .ID }}.html">{{ .Title }}</a></td>
+<td>{{ .Status }}</td>
+<td>{{ if .Priority }}<span class="{{ .Priority }}">{{ .Priority }}</span>{{ end }}</td>
+<td>{{ .DueDate }}</td>
+<td><progress max="100" value="{{ .Percent }}"></progress> {{ .Done }}/{{ .Total }}</td>
+</tr>
+{{ end }}</tbody>
+</table>
+{{ end }}`
+
+const reportProjectPage = `{{ define "content" }}{{ with .Project }}
+<h1>{{ .Title }}</h1>
+<p class="meta">{{ .Status }}{{ if .Priority }} · <span class="{{ .Priority }}">{{ .Priority }}</span>{{ end }}{{ if .DueDate }} · due {{ .DueDate }}{{ end }}{{ if .Area }} · {{ .Area }}{{ end }} {{ template "tags" .Tags }}</p>
+<p><progress max="100" value="{{ .Percent }}"></progress> {{ .Done }} of {{ .Total }} tasks done ({{ .Percent }}%)</p>
+{{ end }}
+{{ if .Tasks }}<h2>Tasks</h2>
+` + reportTaskTable + `{{ end }}
+{{ template "body" . }}
+{{ end }}`
+
+const reportTaskPage = `{{ define "content" }}{{ with .Task }}
+<h1>#{{ .TaskID }} {{ .Title }}</h1>
+<table class="fields">
+<tr><th>Status</th><td>{{ .Status }}</td></tr>
+{{ if .Priority }}<tr><th>Priority</th><td><span class="{{ .Priority }}">{{ .Priority }}</span></td></tr>{{ end }}
+{{ if .DueDate }}<tr><th>Due</th><td{{ if .Overdue }} class="overdue"{{ end }}>{{ .DueDate }}</td></tr>{{ end }}
+{{ if .Start }}<tr><th>Start</th><td>{{ .Start }}</td></tr>{{ end }}
+{{ if .Project }}<tr><th>Project</th><td>{{ .Project }}</td></tr>{{ end }}
+{{ if .Area }}<tr><th>Area</th><td>{{ .Area }}</td></tr>{{ end }}
+{{ if .Assignee }}<tr><th>Assignee</th><td>{{ .Assignee }}</td></tr>{{ end }}
+{{ if .Tags }}<tr><th>Tags</th><td>{{ template "tags" .Tags }}</td></tr>{{ end }}
+</table>
+{{ end }}
+{{ template "body" . }}
+{{ end }}`
+
+const reportNotesPage = `{{ define "content" }}
+<h1>Notes</h1>
+<table>
+<thead><tr><th>Note</th><th>Date</th><th>Tags</th></tr></thead>
+<tbody>
+{{ range .Notes }}<tr>
+<td><a href="notes/{{ .ID }}.html">{{ .Title }}</a></td>
+<td>{{ .Date }}</td>
+<td>{{ template "tags" .Tags }}</td>
+</tr>
+{{ end }}</tbody>
+</table>
+{{ end }}`
+
+const reportNotePage = `{{ define "content" }}{{ with .Note }}
+<h1>{{ .Title }}</h1>
+<p class="meta">{{ .Date }} {{ template "tags" .Tags }}</p>
+{{ end }}
+{{ template "body" . }}
+{{ end }}`
+
+const reportBody = `{{ define "body" }}<article>
+{{ .Body }}
+</article>
+{{ if .Links }}<h2>Linked from</h2>
+<ul>
+{{ range .Links }}<li><a href="{{ .Kind }}s/{{ .ID }}.html">{{ .Title }}</a> <span class="count">{{ .Kind }}</span></li>
+{{ end }}</ul>{{ end }}{{ end }}`
+
+const reportCSS = `body { font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #222; background: #fafafa; }
+header { display: flex; gap: 2em; align-items: center; padding: .8em 1.5em; background: #24292f; color: #fff; }
+header a { color: #ccc; text-decoration: none; margin-right: 1em; }
+header a.active, header a:hover { color: #fff; }
+main { padding: 1em 1.5em; max-width: 1200px; }
+footer { padding: 1em 1.5em; color: #888; font-size: 12px; }
+a { color: #0b62c4; }
+table { border-collapse: collapse; width: 100%; background: #fff; }
+th, td { text-align: left; padding: .35em .6em; border-bottom: 1px solid #e4e4e4; vertical-align: top; }
+table.fields { width: auto; }
+.board { display: flex; gap: 1em; align-items: flex-start; overflow-x: auto; }
+.column { flex: 1; min-width: 200px; background: #eef0f2; border-radius: 6px; padding: .5em; }
+.column h2 { font-size: 14px; text-transform: uppercase; margin: .2em .3em .6em; }
+.card { background: #fff; border-radius: 4px; padding: .5em .6em; margin-bottom: .5em; box-shadow: 0 1px 2px rgba(0,0,0,.1); }
+.card a { text-decoration: none; color: #222; }
+.meta { font-size: 12px; color: #666; }
+.count { font-size: 12px; color: #888; font-weight: normal; }
+.overdue, .overdue .meta { color: #c62828; }
+.card.overdue { border-left: 3px solid #c62828; }
+.p1 { color: #c62828; font-weight: bold; }
+.p2 { color: #e65100; }
+.p3 { color: #1565c0; }
+.tag { display: inline-block; font-size: 12px; background: #e8eaed; border-radius: 3px; padding: 0 .4em; }
+.broken-link { color: #888; border-bottom: 1px dotted #888; }
+article { background: #fff; padding: .5em 1.5em; border-radius: 6px; margin: 1em 0; }
+pre { background: #f3f3f3; padding: .8em; overflow-x: auto; }
+blockquote { border-left: 3px solid #ddd; margin-left: 0; padding-left: 1em; color: #555; }
+`
+
+func newReportTemplate(page string) (*template.Template, error) {
+	return template.New("layout").Parse(reportLayout + page + reportBody)
+}
+
+func toReportTask(task TaskInfo) reportTask {
+	var tags []string
+	for _, tag := range task.Note.Tags {
+		if tag != "task" {
+			tags = append(tags, tag)
+		}
+	}
+	// Pages are named by Denote ID; a task without one falls back to its
+	// task ID, and one with neither gets no page
+	id := task.Note.ID
+	if id == "" && task.TaskID > 0 {
+		id = fmt.Sprintf("task-%d", task.TaskID)
+	}
+	return reportTask{
+		ID:       id,
+		TaskID:   task.TaskID,
+		Title:    task.Note.Title,
+		Status:   task.Status,
+		Priority: task.Priority,
+		DueDate:  task.DueDate,
+		Start:    task.StartDate,
+		Project:  task.Project,
+		Area:     task.Area,
+		Assignee: task.Assignee,
+		Tags:     tags,
+		Overdue:  task.Status != "done" && task.Status != "dropped" && isOverdue(task.DueDate),
+	}
+}
+
+func noteDate(id string) string {
+	if t, err := time.ParseInLocation(denoteIDFormat, id, time.Local); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return ""
+}
+
+type htmlReport struct {
+	config    Config
+	opts      HTMLReportOptions
+	generated string
+	index     map[string]LinkedFile
+	backlinks map[string][]LinkedFile
+	pages     int
+}
+
+// resolve maps a Denote ID to its page. Pages set <base> to the output
+// directory, so the URL is relative to that.
+func (r *htmlReport) resolve(id string) (string, string, bool) {
+	linked, ok := r.index[id]
+	if !ok {
+		return "", "", false
+	}
+	return linked.Kind + "s/" + id + ".html", linked.Title, true
+}
+
+func (r *htmlReport) write(name, page string, data reportPage) error {
+	tmpl, err := newReportTemplate(page)
+	if err != nil {
+		return fmt.Errorf("invalid report template: %w", err)
+	}
+
+	data.Site = r.opts.Title
+	data.Generated = r.generated
+	data.Root = "./"
+	if strings.Contains(name, "/") {
+		data.Root = "../"
+	}
+
+	path := filepath.Join(r.opts.OutDir, filepath.FromSlash(name))
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	r.pages++
+	return nil
+}
+
+// body renders the Markdown body of a file with links to other pages
+func (r *htmlReport) body(path string) template.HTML {
+	body, err := readNoteBody(path)
+	if err != nil {
+		return ""
+	}
+	return template.HTML(renderMarkdown(body, r.resolve))
+}
+
+func generateHTMLReport(config Config, opts HTMLReportOptions) error {
+	if opts.OutDir == "" {
+		return fmt.Errorf("output directory required (-out dir/)")
+	}
+	for _, dir := range []string{"", "tasks", "projects", "notes"} {
+		if err := os.MkdirAll(filepath.Join(opts.OutDir, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	index, err := buildIDIndex(config)
+	if err != nil {
+		return err
+	}
+
+	r := &htmlReport{
+		config:    config,
+		opts:      opts,
+		generated: time.Now().Format("2006-01-02 15:04"),
+		index:     index,
+		backlinks: make(map[string][]LinkedFile),
+	}
+
+	// Backlinks for every page, from one pass over the vault
+	for _, file := range index {
+		content, err := os.ReadFile(file.Path)
+		if err != nil {
+			continue
+		}
+		for _, id := range extractDenoteLinks(string(content)) {
+			if id != file.ID {
+				r.backlinks[id] = append(r.backlinks[id], file)
+			}
+		}
+	}
+	for id := range r.backlinks {
+		links := r.backlinks[id]
+		sort.Slice(links, func(i, j int) bool { return links[i].ID > links[j].ID })
+	}
+
+	tasks, err := findTasks(config, TaskFilters{All: true, SortBy: "priority"})
+	if err != nil {
+		return err
+	}
+	projects, err := findProjects(config, ProjectFilters{All: true, SortBy: "priority"})
+	if err != nil {
+		return err
+	}
+	notes, err := findNotes(config, NoteFilters{SortBy: "created", Reverse: true})
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(opts.OutDir, "style.css"), []byte(reportCSS), 0644); err != nil {
+		return fmt.Errorf("failed to write style.css: %w", err)
+	}
+
+	// Board and overdue list
+	columns := make([]reportColumn, len(boardStatuses))
+	var overdue []reportTask
+	for i, status := range boardStatuses {
+		columns[i].Status = status
+	}
+	for _, task := range tasks {
+		rt := toReportTask(task)
+		if rt.ID == "" {
+			continue
+		}
+		for i := range columns {
+			if columns[i].Status == task.Status {
+				columns[i].Tasks = append(columns[i].Tasks, rt)
+			}
+		}
+		if rt.Overdue {
+			overdue = append(overdue, rt)
+		}
+
+		page := reportPage{Title: rt.Title, Task: &rt, Body: r.body(task.Path), Links: r.backlinks[task.Note.ID]}
+		if err := r.write("tasks/"+rt.ID+".html", reportTaskPage, page); err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool { return overdue[i].DueDate < overdue[j].DueDate })
+
+	if err := r.write("index.html", reportBoardPage, reportPage{Title: "Board", Active: "board", Columns: columns}); err != nil {
+		return err
+	}
+	if err := r.write("overdue.html", reportOverduePage, reportPage{Title: "Overdue", Active: "overdue", Tasks: overdue}); err != nil {
+		return err
+	}
+
+	// Projects with their member tasks
+	var reportProjects []reportProject
+	for _, project := range projects {
+		id := project.Note.ID
+		if id == "" && project.ProjectID > 0 {
+			id = fmt.Sprintf("project-%d", project.ProjectID)
+		}
+		if id == "" {
+			continue
+		}
+		var tags []string
+		for _, tag := range project.Note.Tags {
+			if tag != "project" {
+				tags = append(tags, tag)
+			}
+		}
+		rp := reportProject{
+			ID:       id,
+			Title:    project.Note.Title,
+			Status:   project.Status,
+			Priority: project.Priority,
+			DueDate:  project.DueDate,
+			Area:     project.Area,
+			Tags:     tags,
+		}
+		for _, task := range tasks {
+			if !strings.EqualFold(task.Project, project.Note.Title) {
+				continue
+			}
+			if rt := toReportTask(task); rt.ID != "" {
+				rp.Tasks = append(rp.Tasks, rt)
+			}
+			switch task.Status {
+			case "done":
+				rp.Done++
+				rp.Total++
+			case "dropped":
+			default:
+				rp.Total++
+			}
+		}
+		if rp.Total > 0 {
+			rp.Percent = rp.Done * 100 / rp.Total
+		}
+		reportProjects = append(reportProjects, rp)
+
+		page := reportPage{
+			Title:   rp.Title,
+			Project: &rp,
+			Tasks:   rp.Tasks,
+			Body:    r.body(project.Path),
+			Links:   r.backlinks[project.Note.ID],
+		}
+		if err := r.write("projects/"+id+".html", reportProjectPage, page); err != nil {
+			return err
+		}
+	}
+	if err := r.write("projects.html", reportProjectsPage, reportPage{Title: "Projects", Active: "projects", Projects: reportProjects}); err != nil {
+		return err
+	}
+
+	// Plain notes (tasks and projects may share the notes directory)
+	var reportNotes []reportNote
+	for _, note := range notes {
+		if noteKind(note.Note) != "note" || note.Note.ID == "" {
+			continue
+		}
+		rn := reportNote{
+			ID:       note.Note.ID,
+			Title:    note.Note.Title,
+			Date:     noteDate(note.Note.ID),
+			Tags:     note.Note.Tags,
+			Modified: note.ModTime.Format("2006-01-02"),
+		}
+		reportNotes = append(reportNotes, rn)
+
+		page := reportPage{Title: rn.Title, Note: &rn, Body: r.body(note.Path), Links: r.backlinks[note.Note.ID]}
+		if err := r.write("notes/"+note.Note.ID+".html", reportNotePage, page); err != nil {
+			return err
+		}
+	}
+	if err := r.write("notes.html", reportNotesPage, reportPage{Title: "Notes", Active: "notes", Notes: reportNotes}); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Generated %s in %s\n", success("✓"), count(r.pages, pluralize(r.pages, "page", "pages")), opts.OutDir)
+	fmt.Printf("  %s, %s, %s, %s overdue\n",
+		count(len(tasks), pluralize(len(tasks), "task", "tasks")),
+		count(len(reportProjects), pluralize(len(reportProjects), "project", "projects")),
+		count(len(reportNotes), pluralize(len(reportNotes), "note", "notes")),
+		bold(fmt.Sprintf("%d", len(overdue))))
+	fmt.Printf("→ Open %s\n", filepath.Join(opts.OutDir, "index.html"))
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultWeeklyTemplate renders 'report weekly'
+const defaultWeeklyTemplate = `# Weekly review: {{ .Since }} to {{ .Until }}
+{{ range .Groups }}
+## {{ .Name }}
+{{ if .Completed }}
+### Completed
+{{ range .Completed }}- [x] #{{ .TaskID }} {{ .Title }}
+{{ end }}{{ end }}{{ if .Changed }}
+### Status changes
+{{ range .Changed }}- #{{ .TaskID }} {{ .Title }}: {{ .From }} → {{ .Status }}
+{{ end }}{{ end }}{{ if .New }}
+### New
+{{ range .New }}- [{{ if eq .Status "done" }}x{{ else }} {{ end }}] #{{ .TaskID }} {{ .Title }}{{ if .Priority }} {{ .Priority }}{{ end }}{{ if .DueDate }} (due {{ .DueDate }}){{ end }}
+{{ end }}{{ end }}{{ if .Logs }}
+### Log
+{{ range .Logs }}- {{ .Date }} {{ .Title }}: {{ .Text }}
+{{ end }}{{ end }}{{ if .Overdue }}
+### Overdue
+{{ range .Overdue }}- [ ] #{{ .TaskID }} {{ .Title }}{{ if .Priority }} {{ .Priority }}{{ end }} (due {{ .DueDate }})
+{{ end }}{{ end }}{{ if .DueNextWeek }}
+### Due next week
+{{ range .DueNextWeek }}- [ ] #{{ .TaskID }} {{ .Title }}{{ if .Priority }} {{ .Priority }}{{ end }} (due {{ .DueDate }})
+{{ end }}{{ end }}{{ end }}{{ if not .Groups }}
+Nothing happened.
+{{ end }}`
+
+// WeeklyOptions controls report weekly
+type WeeklyOptions struct {
+	Since    string // 7d, 2w or YYYY-MM-DD
+	GroupBy  string // project or area
+	Template string // template file, overriding [report] weekly_template
+	Output   string
+}
+
+// WeeklyTask is the view of a task available to the weekly template
+type WeeklyTask struct {
+	ID       string
+	TaskID   int
+	Title    string
+	Status   string
+	From     string // previous status, for status changes
+	Priority string
+	DueDate  string
+	Project  string
+	Area     string
+}
+
+// WeeklyLog is a log entry written during the window
+type WeeklyLog struct {
+	Date   string
+	ID     string
+	TaskID int // 0 for project logs
+	Title  string
+	Text   string
+}
+
+// WeeklyGroup collects everything for one project or area
+type WeeklyGroup struct {
+	Name        string
+	Completed   []WeeklyTask
+	Changed     []WeeklyTask
+	New         []WeeklyTask
+	Logs        []WeeklyLog
+	Overdue     []WeeklyTask
+	DueNextWeek []WeeklyTask
+}
+
+type WeeklyReport struct {
+	Since  string
+	Until  string
+	Groups []*WeeklyGroup
+}
+
+// Status changes are recorded as tasks are updated, for the "Status changes"
+// section. A task's first entry holds the status it had before tracking
+// began, with an empty date.
+type statusChange struct {
+	Date   string `json:"date"`
+	Status string `json:"status"`
+}
+
+type statusHistory struct {
+	Tasks map[string][]statusChange `json:"tasks"` // Denote ID -> changes, oldest first
+}
+
+func statusHistoryPath(config Config) string {
+	return filepath.Join(config.TaskDir, ".notes-cli-status-history.json")
+}
+
+func loadStatusHistory(config Config) (*statusHistory, error) {
+	history := &statusHistory{Tasks: make(map[string][]statusChange)}
+
+	data, err := os.ReadFile(statusHistoryPath(config))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", statusHistoryPath(config), err)
+	}
+	if history.Tasks == nil {
+		history.Tasks = make(map[string][]statusChange)
+	}
+
+	return history, nil
+}
+
+func (h *statusHistory) save(config Config) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status history: %w", err)
+	}
+	if err := os.WriteFile(statusHistoryPath(config), data, 0644); err != nil {
+		return fmt.Errorf("failed to write status history: %w", err)
+	}
+	return nil
+}
+
+// recordStatusChange adds a task's status change to the history. Like
+// gitAutoCommit, a failure is reported but doesn't fail the update.
+func recordStatusChange(config Config, id, from, to string) {
+	if from == to || id == "" {
+		return
+	}
+
+	history, err := loadStatusHistory(config)
+	if err == nil {
+		if len(history.Tasks[id]) == 0 {
+			history.Tasks[id] = []statusChange{{Status: from}}
+		}
+		history.Tasks[id] = append(history.Tasks[id], statusChange{Date: time.Now().Format("2006-01-02"), Status: to})
+		err = history.save(config)
+	}
+	if err != nil {
+		fmt.Printf("%s Status change not recorded: %v\n", warning("!"), err)
+	}
+}
+
+// statusBefore returns the last status recorded before date
+func (h *statusHistory) statusBefore(id, date string) string {
+	status := ""
+	for _, change := range h.Tasks[id] {
+		if change.Date >= date {
+			break
+		}
+		status = change.Status
+	}
+	return status
+}
+
+var sinceShorthandPattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// parseSince turns 7d, 2w or a YYYY-MM-DD date into the first day of the window
+func parseSince(since string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if m := sinceShorthandPattern.FindStringSubmatch(since); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, -n), nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", since, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -since %q (use 7d, 2w or YYYY-MM-DD)", since)
+	}
+	return t, nil
+}
+
+func weeklyTask(task TaskInfo) WeeklyTask {
+	return WeeklyTask{
+		ID:       task.Note.ID,
+		TaskID:   task.TaskID,
+		Title:    task.Note.Title,
+		Status:   task.Status,
+		Priority: task.Priority,
+		DueDate:  task.DueDate,
+		Project:  task.Project,
+		Area:     task.Area,
+	}
+}
+
+func buildWeeklyReport(config Config, opts WeeklyOptions, now time.Time) (*WeeklyReport, error) {
+	if opts.GroupBy != "project" && opts.GroupBy != "area" {
+		return nil, fmt.Errorf("invalid -group %q (use project or area)", opts.GroupBy)
+	}
+
+	start, err := parseSince(opts.Since, now)
+	if err != nil {
+		return nil, err
+	}
+	since := start.Format("2006-01-02")
+	today := now.Format("2006-01-02")
+	nextWeek := now.AddDate(0, 0, 7).Format("2006-01-02")
+
+	tasks, err := findTasks(config, TaskFilters{All: true, SortBy: "priority"})
+	if err != nil {
+		return nil, err
+	}
+	projects, err := findProjects(config, ProjectFilters{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := loadStatusHistory(config)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WeeklyReport{Since: since, Until: today}
+	groups := make(map[string]*WeeklyGroup)
+	none := "No " + opts.GroupBy
+	group := func(project, area string) *WeeklyGroup {
+		name := project
+		if opts.GroupBy == "area" {
+			name = area
+		}
+		if name == "" {
+			name = none
+		}
+		g, ok := groups[strings.ToLower(name)]
+		if !ok {
+			g = &WeeklyGroup{Name: name}
+			groups[strings.ToLower(name)] = g
+		}
+		return g
+	}
+
+	for _, task := range tasks {
+		g := group(task.Project, task.Area)
+		view := weeklyTask(task)
+		created := noteDate(task.Note.ID)
+		modified := task.ModTime.Format("2006-01-02")
+
+		previous := history.statusBefore(task.Note.ID, since)
+
+		switch task.Status {
+		case "done":
+			if task.completedOn() >= since {
+				g.Completed = append(g.Completed, view)
+			}
+		case "dropped":
+		default:
+			if task.DueDate != "" && task.DueDate < today {
+				g.Overdue = append(g.Overdue, view)
+			} else if task.DueDate != "" && task.DueDate <= nextWeek {
+				g.DueNextWeek = append(g.DueNextWeek, view)
+			}
+		}
+
+		if created >= since {
+			g.New = append(g.New, view)
+		} else if previous != "" && previous != task.Status && task.Status != "done" {
+			view.From = previous
+			g.Changed = append(g.Changed, view)
+		}
+
+		if modified >= since {
+			g.Logs = append(g.Logs, weeklyLogs(task.Path, task.Note.ID, task.TaskID, task.Note.Title, since)...)
+		}
+	}
+
+	for _, project := range projects {
+		if project.ModTime.Format("2006-01-02") < since {
+			continue
+		}
+		g := group(project.Note.Title, project.Area)
+		g.Logs = append(g.Logs, weeklyLogs(project.Path, project.Note.ID, 0, project.Note.Title, since)...)
+	}
+
+	var names []string
+	for key, g := range groups {
+		if len(g.Completed)+len(g.Changed)+len(g.New)+len(g.Logs)+len(g.Overdue)+len(g.DueNextWeek) == 0 {
+			continue
+		}
+		sort.SliceStable(g.Logs, func(i, j int) bool { return g.Logs[i].Date < g.Logs[j].Date })
+		sort.SliceStable(g.Overdue, func(i, j int) bool { return g.Overdue[i].DueDate < g.Overdue[j].DueDate })
+		sort.SliceStable(g.DueNextWeek, func(i, j int) bool { return g.DueNextWeek[i].DueDate < g.DueNextWeek[j].DueDate })
+		names = append(names, key)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// The group without a project or area goes last
+		if groups[names[i]].Name == none || groups[names[j]].Name == none {
+			return groups[names[j]].Name == none && groups[names[i]].Name != none
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		report.Groups = append(report.Groups, groups[name])
+	}
+
+	return report, nil
+}
+
+// weeklyLogs returns the log entries in a file written on or after since
+func weeklyLogs(path, id string, taskID int, title, since string) []WeeklyLog {
+	body, err := readNoteBody(path)
+	if err != nil {
+		return nil
+	}
+
+	var logs []WeeklyLog
+	for _, line := range strings.Split(body, "\n") {
+		m := logLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || m[1] < since {
+			continue
+		}
+		logs = append(logs, WeeklyLog{Date: m[1], ID: id, TaskID: taskID, Title: title, Text: m[2]})
+	}
+	return logs
+}
+
+func weeklyReport(config Config, opts WeeklyOptions) error {
+	tmplText := config.TOMLConfig.Report.WeeklyTemplate
+	if opts.Template != "" {
+		data, err := os.ReadFile(opts.Template)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		tmplText = string(data)
+	}
+	if tmplText == "" {
+		tmplText = defaultWeeklyTemplate
+	}
+
+	tmpl, err := template.New("weekly").Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("invalid weekly report template: %w", err)
+	}
+
+	report, err := buildWeeklyReport(config, opts, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, report); err != nil {
+		return fmt.Errorf("failed to render weekly report: %w", err)
+	}
+
+	if opts.Output == "" {
+		fmt.Print(result.String())
+		return nil
+	}
+
+	if err := os.WriteFile(opts.Output, []byte(result.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.Output, err)
+	}
+	fmt.Printf("%s Wrote weekly report to %s\n", success("✓"), opts.Output)
+
+	return nil
+}
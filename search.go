@@ -0,0 +1,441 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type SearchOptions struct {
+	Query   string
+	Type    string // task, project, note, or empty for all
+	Limit   int
+	JSON    bool
+	Rebuild bool
+}
+
+type SearchResult struct {
+	Path    string   `json:"path"`
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	Tags    []string `json:"tags"`
+	Score   float64  `json:"score"`
+	Snippet string   `json:"snippet"`
+}
+
+// searchTerm is a single word or quoted phrase in a query
+type searchTerm struct {
+	terms  []string
+	negate bool
+}
+
+// searchQuery is a disjunction of clauses; each clause matches when all of
+// its positive terms match and none of its negated terms do.
+type searchQuery struct {
+	clauses [][]searchTerm
+}
+
+// parseSearchQuery parses queries such as:
+//
+//	budget review             both words
+//	"quarterly budget"        exact phrase
+//	budget OR forecast        either word
+//	budget -draft             budget but not draft (also: budget NOT draft)
+func parseSearchQuery(query string) (searchQuery, error) {
+	var q searchQuery
+	var clause []searchTerm
+	negateNext := false
+
+	for _, token := range splitQueryTokens(query) {
+		switch {
+		case token.text == "OR" && !token.quoted:
+			if len(clause) > 0 {
+				q.clauses = append(q.clauses, clause)
+			}
+			clause = nil
+			continue
+		case token.text == "AND" && !token.quoted:
+			continue
+		case token.text == "NOT" && !token.quoted:
+			negateNext = true
+			continue
+		}
+
+		text := token.text
+		negate := negateNext
+		negateNext = false
+		if !token.quoted && strings.HasPrefix(text, "-") {
+			negate = true
+			text = text[1:]
+		}
+
+		terms := tokenize(text)
+		if len(terms) == 0 {
+			continue
+		}
+		clause = append(clause, searchTerm{terms: terms, negate: negate})
+	}
+
+	if len(clause) > 0 {
+		q.clauses = append(q.clauses, clause)
+	}
+
+	if len(q.clauses) == 0 {
+		return q, fmt.Errorf("empty search query")
+	}
+
+	for _, c := range q.clauses {
+		positive := false
+		for _, t := range c {
+			if !t.negate {
+				positive = true
+			}
+		}
+		if !positive {
+			return q, fmt.Errorf("each part of the query needs at least one term that is not excluded")
+		}
+	}
+
+	return q, nil
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+func splitQueryTokens(query string) []queryToken {
+	var tokens []queryToken
+	var current strings.Builder
+	inQuotes := false
+	negateQuote := false
+
+	flush := func(quoted bool) {
+		if current.Len() > 0 || quoted {
+			text := current.String()
+			if quoted && negateQuote {
+				// -"some phrase" excludes the phrase
+				tokens = append(tokens, queryToken{text: "NOT"})
+			}
+			tokens = append(tokens, queryToken{text: text, quoted: quoted})
+			current.Reset()
+		}
+		negateQuote = false
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if inQuotes {
+				flush(true)
+			} else {
+				if current.String() == "-" {
+					current.Reset()
+					negateQuote = true
+				} else {
+					flush(false)
+				}
+			}
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			flush(false)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush(inQuotes)
+
+	return tokens
+}
+
+// positiveTerms returns every term the query looks for, for highlighting
+func (q searchQuery) positiveTerms() map[string]bool {
+	terms := make(map[string]bool)
+	for _, clause := range q.clauses {
+		for _, t := range clause {
+			if t.negate {
+				continue
+			}
+			for _, term := range t.terms {
+				terms[term] = true
+			}
+		}
+	}
+	return terms
+}
+
+func searchVault(config Config, opts SearchOptions) ([]SearchResult, error) {
+	query, err := parseSearchQuery(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := newSearchIndex()
+	if !opts.Rebuild {
+		idx = loadSearchIndex(config)
+	}
+
+	changed, err := idx.refresh(config)
+	if err != nil {
+		return nil, err
+	}
+	if changed || opts.Rebuild {
+		if err := idx.save(config); err != nil {
+			// Non-fatal error
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	avgLength := idx.averageLength()
+	docTokens := make(map[string][]string)
+	tokensFor := func(path string) []string {
+		if tokens, ok := docTokens[path]; ok {
+			return tokens
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		tokens := tokenize(string(content))
+		docTokens[path] = tokens
+		return tokens
+	}
+
+	matches := func(t searchTerm, path string) bool {
+		for _, term := range t.terms {
+			if idx.Postings[term][path] == 0 {
+				return false
+			}
+		}
+		if len(t.terms) == 1 {
+			return true
+		}
+		return containsSequence(tokensFor(path), t.terms)
+	}
+
+	scoreTerm := func(term, path string) float64 {
+		// BM25
+		const k1, b = 1.2, 0.75
+		postings := idx.Postings[term]
+		tf := float64(postings[path])
+		if tf == 0 {
+			return 0
+		}
+		n := float64(len(postings))
+		idf := math.Log(1 + (float64(len(idx.Docs))-n+0.5)/(n+0.5))
+		length := float64(idx.Docs[path].Length)
+		norm := 1.0
+		if avgLength > 0 {
+			norm = 1 - b + b*length/avgLength
+		}
+		return idf * tf * (k1 + 1) / (tf + k1*norm)
+	}
+
+	scores := make(map[string]float64)
+	for _, clause := range query.clauses {
+		for path, doc := range idx.Docs {
+			if opts.Type != "" && doc.Kind != opts.Type {
+				continue
+			}
+
+			matched := true
+			score := 0.0
+			for _, t := range clause {
+				if matches(t, path) == t.negate {
+					matched = false
+					break
+				}
+				if t.negate {
+					continue
+				}
+				termScore := 0.0
+				for _, term := range t.terms {
+					termScore += scoreTerm(term, path)
+				}
+				if len(t.terms) > 1 {
+					// Reward exact phrases
+					termScore *= 1.5
+				}
+				score += termScore
+			}
+
+			if matched {
+				scores[path] += score
+			}
+		}
+	}
+
+	var results []SearchResult
+	for path, score := range scores {
+		doc := idx.Docs[path]
+		results = append(results, SearchResult{
+			Path:  path,
+			ID:    doc.ID,
+			Title: doc.Title,
+			Type:  doc.Kind,
+			Tags:  doc.Tags,
+			Score: math.Round(score*1000) / 1000,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	terms := query.positiveTerms()
+	for i := range results {
+		results[i].Snippet = searchSnippet(results[i].Path, terms)
+	}
+
+	return results, nil
+}
+
+func containsSequence(tokens, seq []string) bool {
+	for i := 0; i+len(seq) <= len(tokens); i++ {
+		found := true
+		for j := range seq {
+			if tokens[i+j] != seq[j] {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// searchSnippet returns the first body line containing a search term,
+// trimmed to a window around the match
+func searchSnippet(path string, terms map[string]bool) string {
+	body, err := readNoteBody(path)
+	if err != nil {
+		return ""
+	}
+
+	const window = 80
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		start, _, ok := findTerm(line, terms)
+		if !ok {
+			continue
+		}
+
+		runes := []rune(line)
+		matchRune := len([]rune(line[:start]))
+		from := matchRune - window/3
+		if from < 0 {
+			from = 0
+		}
+		to := from + window
+		if to > len(runes) {
+			to = len(runes)
+		}
+
+		snippet := string(runes[from:to])
+		if from > 0 {
+			snippet = "…" + snippet
+		}
+		if to < len(runes) {
+			snippet += "…"
+		}
+		return snippet
+	}
+
+	return ""
+}
+
+// findTerm locates the first word in text that is one of terms, returning
+// its byte offsets
+func findTerm(text string, terms map[string]bool) (int, int, bool) {
+	start := -1
+	for i, r := range text + " " {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start == -1 {
+			start = i
+		} else if !isWord && start != -1 {
+			if terms[strings.ToLower(text[start:i])] {
+				return start, i, true
+			}
+			start = -1
+		}
+	}
+	return 0, 0, false
+}
+
+// highlightTerms wraps every occurrence of the search terms in color
+func highlightTerms(text string, terms map[string]bool) string {
+	var b strings.Builder
+	for {
+		start, end, ok := findTerm(text, terms)
+		if !ok {
+			b.WriteString(text)
+			break
+		}
+		b.WriteString(text[:start])
+		b.WriteString(brightYellow(bold(text[start:end])))
+		text = text[end:]
+	}
+	return b.String()
+}
+
+func runSearch(config Config, opts SearchOptions) error {
+	if opts.Type != "" && opts.Type != "task" && opts.Type != "project" && opts.Type != "note" {
+		return fmt.Errorf("invalid type: %s (must be task, project, or note)", opts.Type)
+	}
+
+	results, err := searchVault(config, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		if results == nil {
+			results = []SearchResult{}
+		}
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No matches for: %s\n", opts.Query)
+		return nil
+	}
+
+	query, _ := parseSearchQuery(opts.Query)
+	terms := query.positiveTerms()
+
+	fmt.Printf("%s %s (%s):\n\n", bold("Search results for"), info(opts.Query), count(len(results), pluralize(len(results), "match", "matches")))
+
+	for i, result := range results {
+		fmt.Printf("  %s %s %s\n", index(i+1), linkKind(result.Type), highlightTerms(result.Title, terms))
+		if result.Snippet != "" {
+			fmt.Printf("       %s\n", highlightTerms(result.Snippet, terms))
+		}
+		fmt.Printf("       %s\n", filename(result.Path))
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const searchIndexVersion = 1
+
+// Field weights applied to term frequencies when indexing
+const (
+	titleWeight       = 3
+	tagWeight         = 2
+	frontmatterWeight = 1
+	bodyWeight        = 1
+)
+
+// SearchIndex is an inverted index over every file in the vault. It is
+// persisted in the task directory and refreshed incrementally by mtime.
+type SearchIndex struct {
+	Version  int                       `json:"version"`
+	Docs     map[string]*SearchDoc     `json:"docs"`
+	Postings map[string]map[string]int `json:"postings"` // term -> path -> weighted frequency
+}
+
+type SearchDoc struct {
+	Path    string    `json:"path"`
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Kind    string    `json:"kind"`
+	Tags    []string  `json:"tags"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+	Length  int       `json:"length"`
+	Terms   []string  `json:"terms"`
+}
+
+func searchIndexPath(config Config) string {
+	return filepath.Join(config.TaskDir, ".notes-cli-search-index.json")
+}
+
+func newSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		Version:  searchIndexVersion,
+		Docs:     make(map[string]*SearchDoc),
+		Postings: make(map[string]map[string]int),
+	}
+}
+
+func loadSearchIndex(config Config) *SearchIndex {
+	data, err := os.ReadFile(searchIndexPath(config))
+	if err != nil {
+		return newSearchIndex()
+	}
+
+	var index SearchIndex
+	if err := json.Unmarshal(data, &index); err != nil || index.Version != searchIndexVersion {
+		// Unreadable or outdated index, start over
+		return newSearchIndex()
+	}
+	if index.Docs == nil {
+		index.Docs = make(map[string]*SearchDoc)
+	}
+	if index.Postings == nil {
+		index.Postings = make(map[string]map[string]int)
+	}
+
+	return &index
+}
+
+func (idx *SearchIndex) save(config Config) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+
+	if err := os.WriteFile(searchIndexPath(config), data, 0644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+
+	return nil
+}
+
+// refresh brings the index up to date with the vault, reindexing only files
+// whose mtime or size changed. It reports whether anything changed.
+func (idx *SearchIndex) refresh(config Config) (bool, error) {
+	files, err := vaultFiles(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	changed := false
+	present := make(map[string]bool)
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		present[file] = true
+
+		if doc, ok := idx.Docs[file]; ok && doc.ModTime.Equal(info.ModTime()) && doc.Size == info.Size() {
+			continue
+		}
+
+		idx.remove(file)
+		if err := idx.add(file, info); err != nil {
+			continue
+		}
+		changed = true
+	}
+
+	// Drop files that no longer exist
+	for path := range idx.Docs {
+		if !present[path] {
+			idx.remove(path)
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+func (idx *SearchIndex) remove(path string) {
+	doc, ok := idx.Docs[path]
+	if !ok {
+		return
+	}
+
+	for _, term := range doc.Terms {
+		if postings, ok := idx.Postings[term]; ok {
+			delete(postings, path)
+			if len(postings) == 0 {
+				delete(idx.Postings, term)
+			}
+		}
+	}
+
+	delete(idx.Docs, path)
+}
+
+func (idx *SearchIndex) add(path string, info os.FileInfo) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	frontmatter, body := splitFrontmatter(string(content))
+
+	var fields map[string]interface{}
+	if frontmatter != "" {
+		// Unparseable frontmatter is still indexed as plain text
+		if err := yaml.Unmarshal([]byte(frontmatter), &fields); err != nil {
+			fields = nil
+			body = frontmatter + "\n" + body
+		}
+	}
+
+	doc := &SearchDoc{
+		Path:    path,
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+	}
+
+	if note, err := noteIdentity(path); err == nil {
+		doc.ID = note.ID
+		doc.Title = note.Title
+		doc.Tags = note.Tags
+		doc.Kind = noteKind(note)
+	} else {
+		doc.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		doc.Kind = "note"
+	}
+
+	freqs := make(map[string]int)
+	addText := func(text string, weight int) {
+		for _, term := range tokenize(text) {
+			freqs[term] += weight
+			doc.Length++
+		}
+	}
+
+	addText(doc.Title, titleWeight)
+	addText(strings.Join(doc.Tags, " "), tagWeight)
+	for key, value := range fields {
+		if key == "title" || key == "tags" || key == "id" {
+			continue
+		}
+		addText(flattenValue(value), frontmatterWeight)
+	}
+	addText(body, bodyWeight)
+
+	for term, freq := range freqs {
+		postings, ok := idx.Postings[term]
+		if !ok {
+			postings = make(map[string]int)
+			idx.Postings[term] = postings
+		}
+		postings[path] = freq
+		doc.Terms = append(doc.Terms, term)
+	}
+	sort.Strings(doc.Terms)
+
+	idx.Docs[path] = doc
+	return nil
+}
+
+// flattenValue renders a YAML value as searchable text
+func flattenValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, flattenValue(item))
+		}
+		return strings.Join(parts, " ")
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, flattenValue(item))
+		}
+		return strings.Join(parts, " ")
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tokenize lowercases text and splits it into letter/digit terms
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (idx *SearchIndex) averageLength() float64 {
+	if len(idx.Docs) == 0 {
+		return 0
+	}
+	total := 0
+	for _, doc := range idx.Docs {
+		total += doc.Length
+	}
+	return float64(total) / float64(len(idx.Docs))
+}
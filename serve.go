@@ -0,0 +1,997 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The API exposes the vault as JSON:
+//
+//	GET    /api/tasks              list tasks (query: status, priority, project, area, tag, due, overdue, soon, sort, reverse, all)
+//	POST   /api/tasks              create a task
+//	GET    /api/tasks/{task_id}    task with body
+//	PATCH  /api/tasks/{task_id}    update fields
+//	POST   /api/tasks/{task_id}/log
+//	GET    /api/projects           list projects (query: status, soon, sort, reverse, all)
+//	POST   /api/projects           create a project
+//	GET    /api/projects/{project_id}
+//	PATCH  /api/projects/{project_id}
+//	POST   /api/projects/{project_id}/log
+//	GET    /api/notes              list notes (query: tag, sort, reverse)
+//	POST   /api/notes              create a note
+//	GET    /api/notes/{denote_id}
+//	PATCH  /api/notes/{denote_id}  update title, signature, tags or body
+//	POST   /api/notes/{denote_id}/log
+//
+// Single resources carry an ETag. PATCH requires it as If-Match, so a client
+// can't overwrite changes it hasn't seen; log entries only add to a file, so
+// If-Match is optional there.
+//
+// Writes need a JSON Content-Type, and requests from web pages on other
+// origins are refused (see checkOrigin).
+//
+// With a feed file (written by 'export ics -o'), GET /calendar.ics serves it
+// as a calendar subscription.
+type ServeOptions struct {
+	Addr  string
+	Token string
+	Feed  string
+}
+
+type apiServer struct {
+	config Config
+	token  string
+	// mu serializes writes so concurrent requests don't race on files or the ID counter
+	mu sync.Mutex
+}
+
+// apiError is returned as {"error": "..."} with its status code
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &apiError{http.StatusNotFound, fmt.Sprintf(format, args...)}
+}
+
+type TaskJSON struct {
+	TaskID    int       `json:"task_id"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Signature string    `json:"signature,omitempty"`
+	Tags      []string  `json:"tags"`
+	Status    string    `json:"status"`
+	Priority  string    `json:"priority,omitempty"`
+	DueDate   string    `json:"due_date,omitempty"`
+	StartDate string    `json:"start_date,omitempty"`
+	Completed string    `json:"completed_date,omitempty"`
+	Estimate  int       `json:"estimate,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	Area      string    `json:"area,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	Remind    string    `json:"remind,omitempty"`
+	Path      string    `json:"path"`
+	Modified  time.Time `json:"modified"`
+	Body      *string   `json:"body,omitempty"`
+}
+
+type ProjectJSON struct {
+	ProjectID int       `json:"project_id"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Signature string    `json:"signature,omitempty"`
+	Tags      []string  `json:"tags"`
+	Status    string    `json:"status"`
+	Priority  string    `json:"priority,omitempty"`
+	StartDate string    `json:"start_date,omitempty"`
+	DueDate   string    `json:"due_date,omitempty"`
+	Area      string    `json:"area,omitempty"`
+	Path      string    `json:"path"`
+	Modified  time.Time `json:"modified"`
+	Body      *string   `json:"body,omitempty"`
+}
+
+type NoteJSON struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Signature string    `json:"signature,omitempty"`
+	Tags      []string  `json:"tags"`
+	Path      string    `json:"path"`
+	Modified  time.Time `json:"modified"`
+	Body      *string   `json:"body,omitempty"`
+}
+
+// Request bodies. Dates accept anything parseDate does ("friday", "3d").
+// On update, empty fields are left unchanged and tags uses the CLI syntax
+// ("urgent,-later").
+type taskRequest struct {
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  string   `json:"priority"`
+	DueDate   string   `json:"due_date"`
+	StartDate string   `json:"start_date"`
+	Estimate  int      `json:"estimate"`
+	Project   string   `json:"project"`
+	Area      string   `json:"area"`
+	Assignee  string   `json:"assignee"`
+	Tags      []string `json:"tags"`
+	Body      string   `json:"body"`
+}
+
+type taskUpdateRequest struct {
+	Status    string `json:"status"`
+	Priority  string `json:"priority"`
+	DueDate   string `json:"due_date"`
+	StartDate string `json:"start_date"`
+	Estimate  int    `json:"estimate"`
+	Project   string `json:"project"`
+	Area      string `json:"area"`
+	Assignee  string `json:"assignee"`
+	Tags      string `json:"tags"`
+}
+
+type projectRequest struct {
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  string   `json:"priority"`
+	StartDate string   `json:"start_date"`
+	DueDate   string   `json:"due_date"`
+	Area      string   `json:"area"`
+	Tags      []string `json:"tags"`
+	Body      string   `json:"body"`
+}
+
+type projectUpdateRequest struct {
+	Status    string `json:"status"`
+	Priority  string `json:"priority"`
+	StartDate string `json:"start_date"`
+	DueDate   string `json:"due_date"`
+	Area      string `json:"area"`
+	Tags      string `json:"tags"`
+}
+
+type noteRequest struct {
+	Title     string   `json:"title"`
+	Signature string   `json:"signature"`
+	Tags      []string `json:"tags"`
+	Body      string   `json:"body"`
+}
+
+// noteUpdateRequest leaves empty fields alone. Tags add and remove like the
+// tags of a task PATCH ("new,-old"); body replaces the whole body.
+type noteUpdateRequest struct {
+	Title     string  `json:"title"`
+	Signature string  `json:"signature"`
+	Tags      string  `json:"tags"`
+	Body      *string `json:"body"`
+}
+
+type logRequest struct {
+	Entry string `json:"entry"`
+}
+
+func serve(config Config, opts ServeOptions) error {
+	if opts.Token == "" {
+		opts.Token = os.Getenv("NOTES_CLI_TOKEN")
+	}
+
+	s := &apiServer{config: config, token: opts.Token}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/tasks", s.handle(s.tasks))
+	mux.HandleFunc("/api/tasks/", s.handle(s.task))
+	mux.HandleFunc("/api/projects", s.handle(s.projects))
+	mux.HandleFunc("/api/projects/", s.handle(s.project))
+	mux.HandleFunc("/api/notes", s.handle(s.notes))
+	mux.HandleFunc("/api/notes/", s.handle(s.note))
+	if opts.Feed != "" {
+		mux.HandleFunc("/calendar.ics", s.handle(s.feed(opts.Feed)))
+	}
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+
+	fmt.Printf("%s Serving %s on %s\n", success("✓"), config.NotesDir, bold("http://"+listener.Addr().String()+"/api/"))
+	if s.token == "" {
+		if host, _, err := net.SplitHostPort(opts.Addr); err == nil && !isLoopback(host) {
+			fmt.Fprintf(os.Stderr, "Warning: serving on %s without a token; only requests to localhost will be answered (use -token)\n", opts.Addr)
+		}
+	} else {
+		fmt.Println("  Requests need: Authorization: Bearer <token>")
+	}
+
+	return http.Serve(listener, mux)
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handle wraps an endpoint with origin and authentication checks, error
+// encoding and logging
+func (s *apiServer) handle(endpoint func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		w := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+		err := s.checkOrigin(r)
+		if err == nil && !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			err = &apiError{http.StatusUnauthorized, "missing or invalid bearer token"}
+		}
+		if err == nil {
+			err = checkContentType(r)
+		}
+		if err == nil {
+			err = endpoint(w, r)
+		}
+
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *apiError
+			var invalid *invalidError
+			if errors.As(err, &apiErr) {
+				status = apiErr.status
+			} else if errors.As(err, &invalid) {
+				status = http.StatusUnprocessableEntity
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+		}
+
+		log.Printf("%s %s %d %s", r.Method, r.URL.Path, w.status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// statusRecorder remembers the status code for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// checkOrigin stops web pages from using the API through the browser. A
+// page on another site can send requests to localhost, and with DNS
+// rebinding it can read the answers too, so without a token only requests
+// addressed to a loopback host are served, and requests from a page on
+// another origin never are.
+func (s *apiServer) checkOrigin(r *http.Request) error {
+	if s.token == "" && !isLoopback(requestHost(r.Host)) {
+		return &apiError{http.StatusForbidden, fmt.Sprintf("host %s is not allowed without a token", r.Host)}
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return &apiError{http.StatusForbidden, fmt.Sprintf("cross-origin requests from %s are not allowed", origin)}
+		}
+	}
+	return nil
+}
+
+// requestHost strips the port from a Host header
+func requestHost(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.Trim(hostport, "[]")
+}
+
+// checkContentType requires JSON bodies on writes. Browsers send text/plain
+// and form posts to other sites without asking first; they won't send
+// application/json that way.
+func checkContentType(r *http.Request) error {
+	switch r.Method {
+	case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return &apiError{http.StatusUnsupportedMediaType, "Content-Type must be application/json"}
+	}
+	return nil
+}
+
+func (s *apiServer) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	// Calendar apps can't set headers, so the feed also takes ?token=
+	if r.URL.Path == "/calendar.ics" && given == "" {
+		given = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) error {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	return &apiError{http.StatusMethodNotAllowed, "method not allowed"}
+}
+
+// splitResourcePath splits "/api/tasks/42/log" into "42" and "log"
+func splitResourcePath(path, prefix string) (string, string) {
+	rest := strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	id, action, _ := strings.Cut(rest, "/")
+	return id, action
+}
+
+// fileETag identifies the current contents of a file
+func fileETag(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`, nil
+}
+
+// checkIfMatch rejects a write when the client's If-Match doesn't match the
+// file, meaning it was changed since the client read it. PATCH must send
+// If-Match ("*" to skip the check on purpose).
+func checkIfMatch(r *http.Request, path string) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" && r.Method == http.MethodPatch {
+		return &apiError{http.StatusPreconditionRequired, "If-Match is required; send the ETag from a GET"}
+	}
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+	current, err := fileETag(path)
+	if err != nil {
+		return err
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return nil
+		}
+	}
+	return &apiError{http.StatusPreconditionFailed, "file was modified since it was read; fetch it again"}
+}
+
+// writeResource sends a single file with its ETag
+func writeResource(w http.ResponseWriter, status int, path string, v interface{}) error {
+	etag, err := fileETag(path)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("ETag", etag)
+	writeJSON(w, status, v)
+	return nil
+}
+
+func validateDate(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	date, err := parseDate(value)
+	if err != nil {
+		return "", badRequest("invalid %s: %v", field, err)
+	}
+	return date, nil
+}
+
+func queryBool(r *http.Request, key string) bool {
+	value, _ := strconv.ParseBool(r.URL.Query().Get(key))
+	return value
+}
+
+func queryInt(r *http.Request, key string) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, badRequest("invalid %s: %s", key, value)
+	}
+	return n, nil
+}
+
+func withBody(path string) (*string, error) {
+	body, err := readNoteBody(path)
+	if err != nil {
+		return nil, err
+	}
+	return &body, nil
+}
+
+// feed serves the calendar file written by 'export ics -o'
+func (s *apiServer) feed(path string) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return methodNotAllowed(w, http.MethodGet)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return notFound("calendar feed not found: %s", path)
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		http.ServeContent(w, r, "calendar.ics", info.ModTime(), file)
+		return nil
+	}
+}
+
+// Tasks
+
+func taskJSON(task *TaskInfo) TaskJSON {
+	return TaskJSON{
+		TaskID:    task.TaskID,
+		ID:        task.Note.ID,
+		Title:     task.Note.Title,
+		Signature: task.Note.Signature,
+		Tags:      task.Note.Tags,
+		Status:    task.Status,
+		Priority:  task.Priority,
+		DueDate:   task.DueDate,
+		StartDate: task.StartDate,
+		Completed: task.CompletedDate,
+		Estimate:  task.Estimate,
+		Project:   task.Project,
+		Area:      task.Area,
+		Assignee:  task.Assignee,
+		Remind:    task.Remind,
+		Path:      task.Path,
+		Modified:  task.ModTime,
+	}
+}
+
+func (s *apiServer) tasks(w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		soon, err := queryInt(r, "soon")
+		if err != nil {
+			return err
+		}
+		filters := TaskFilters{
+			Status:    q.Get("status"),
+			Priority:  q.Get("priority"),
+			Project:   q.Get("project"),
+			Area:      q.Get("area"),
+			Tag:       q.Get("tag"),
+			DueFilter: q.Get("due"),
+			Overdue:   queryBool(r, "overdue"),
+			All:       queryBool(r, "all"),
+			SortBy:    q.Get("sort"),
+			Reverse:   queryBool(r, "reverse"),
+			SoonDays:  soon,
+		}
+		if filters.SortBy == "" {
+			filters.SortBy = "modified"
+		}
+		// Same default as 'task list'
+		if !filters.All && filters.Status == "" {
+			filters.Status = "open"
+		}
+
+		tasks, err := findTasks(s.config, filters)
+		if err != nil {
+			return err
+		}
+		result := []TaskJSON{}
+		for i := range tasks {
+			result = append(result, taskJSON(&tasks[i]))
+		}
+		writeJSON(w, http.StatusOK, result)
+		return nil
+
+	case http.MethodPost:
+		var req taskRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Title) == "" {
+			return badRequest("title is required")
+		}
+		if req.Status != "" && !isValidStatus(req.Status) {
+			return badRequest("invalid status: %s", req.Status)
+		}
+
+		meta := TaskMetadata{
+			Status:   req.Status,
+			Priority: req.Priority,
+			Estimate: req.Estimate,
+			Project:  req.Project,
+			Area:     req.Area,
+			Assignee: req.Assignee,
+		}
+		var err error
+		if meta.DueDate, err = validateDate("due_date", req.DueDate); err != nil {
+			return err
+		}
+		if meta.StartDate, err = validateDate("start_date", req.StartDate); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		task, err := createTaskSilently(s.config, req.Title, meta, req.Tags, req.Body)
+		if err != nil {
+			return err
+		}
+		result := taskJSON(task)
+		if result.Body, err = withBody(task.Path); err != nil {
+			return err
+		}
+		w.Header().Set("Location", fmt.Sprintf("/api/tasks/%d", task.TaskID))
+		return writeResource(w, http.StatusCreated, task.Path, result)
+	}
+
+	return methodNotAllowed(w, http.MethodGet, http.MethodPost)
+}
+
+func (s *apiServer) task(w http.ResponseWriter, r *http.Request) error {
+	idStr, action := splitResourcePath(r.URL.Path, "/api/tasks/")
+	taskID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return notFound("invalid task ID: %s", idStr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := findTaskByID(s.config, taskID)
+	if err != nil {
+		return notFound("%v", err)
+	}
+	path := task.Path
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+
+	case action == "" && r.Method == http.MethodPatch:
+		var req taskUpdateRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if req.Status != "" && !isValidStatus(req.Status) {
+			return badRequest("invalid status: %s", req.Status)
+		}
+		if req.Priority != "" && !isValidPriority(req.Priority) {
+			return badRequest("invalid priority: %s (must be p1, p2, or p3)", req.Priority)
+		}
+		if req.Estimate != 0 && !isValidEstimate(req.Estimate) {
+			return badRequest("invalid estimate: %d (must be fibonacci: 1,2,3,5,8,13)", req.Estimate)
+		}
+		updates := TaskMetadata{
+			Status:   req.Status,
+			Priority: req.Priority,
+			Estimate: req.Estimate,
+			Project:  req.Project,
+			Area:     req.Area,
+			Assignee: req.Assignee,
+		}
+		if updates.DueDate, err = validateDate("due_date", req.DueDate); err != nil {
+			return err
+		}
+		if updates.StartDate, err = validateDate("start_date", req.StartDate); err != nil {
+			return err
+		}
+
+		if err := checkIfMatch(r, path); err != nil {
+			return err
+		}
+		if _, err := applyTaskUpdate(s.config, path, updates, req.Tags); err != nil {
+			return err
+		}
+
+	case action == "log" && r.Method == http.MethodPost:
+		var req logRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Entry) == "" {
+			return badRequest("entry is required")
+		}
+		if err := checkIfMatch(r, path); err != nil {
+			return err
+		}
+		if err := addLogEntry(s.config, path, req.Entry); err != nil {
+			return err
+		}
+
+	case action == "":
+		return methodNotAllowed(w, http.MethodGet, http.MethodPatch)
+	case action == "log":
+		return methodNotAllowed(w, http.MethodPost)
+	default:
+		return notFound("unknown action: %s", action)
+	}
+
+	// Re-read, as updates can rename the file
+	task, err = findTaskByID(s.config, taskID)
+	if err != nil {
+		return err
+	}
+	result := taskJSON(task)
+	if result.Body, err = withBody(task.Path); err != nil {
+		return err
+	}
+	return writeResource(w, http.StatusOK, task.Path, result)
+}
+
+// Projects
+
+func projectJSON(project *ProjectInfo) ProjectJSON {
+	return ProjectJSON{
+		ProjectID: project.ProjectID,
+		ID:        project.Note.ID,
+		Title:     project.Note.Title,
+		Signature: project.Note.Signature,
+		Tags:      project.Note.Tags,
+		Status:    project.Status,
+		Priority:  project.Priority,
+		StartDate: project.StartDate,
+		DueDate:   project.DueDate,
+		Area:      project.Area,
+		Path:      project.Path,
+		Modified:  project.ModTime,
+	}
+}
+
+func (s *apiServer) projects(w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet:
+		soon, err := queryInt(r, "soon")
+		if err != nil {
+			return err
+		}
+		filters := ProjectFilters{
+			Status:   r.URL.Query().Get("status"),
+			All:      queryBool(r, "all"),
+			SoonDays: soon,
+			SortBy:   r.URL.Query().Get("sort"),
+			Reverse:  queryBool(r, "reverse"),
+		}
+		if filters.SortBy == "" {
+			filters.SortBy = "modified"
+		}
+		// Same default as 'project list'
+		if !filters.All && filters.Status == "" {
+			filters.Status = "active"
+		}
+
+		projects, err := findProjects(s.config, filters)
+		if err != nil {
+			return err
+		}
+		result := []ProjectJSON{}
+		for i := range projects {
+			result = append(result, projectJSON(&projects[i]))
+		}
+		writeJSON(w, http.StatusOK, result)
+		return nil
+
+	case http.MethodPost:
+		var req projectRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Title) == "" {
+			return badRequest("title is required")
+		}
+		if req.Status != "" && !isValidProjectStatus(req.Status) {
+			return badRequest("invalid status: %s", req.Status)
+		}
+		if req.Priority != "" && !isValidPriority(req.Priority) {
+			return badRequest("invalid priority: %s (must be p1, p2, or p3)", req.Priority)
+		}
+
+		meta := ProjectMetadata{
+			Status:   req.Status,
+			Priority: req.Priority,
+			Area:     req.Area,
+		}
+		var err error
+		if meta.DueDate, err = validateDate("due_date", req.DueDate); err != nil {
+			return err
+		}
+		if meta.StartDate, err = validateDate("start_date", req.StartDate); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		_, path, err := writeProject(s.config, req.Title, meta, req.Tags, req.Body, true)
+		if err != nil {
+			return err
+		}
+		project, err := parseProjectFile(path)
+		if err != nil {
+			return err
+		}
+		result := projectJSON(project)
+		if result.Body, err = withBody(project.Path); err != nil {
+			return err
+		}
+		w.Header().Set("Location", fmt.Sprintf("/api/projects/%d", project.ProjectID))
+		return writeResource(w, http.StatusCreated, project.Path, result)
+	}
+
+	return methodNotAllowed(w, http.MethodGet, http.MethodPost)
+}
+
+func (s *apiServer) project(w http.ResponseWriter, r *http.Request) error {
+	idStr, action := splitResourcePath(r.URL.Path, "/api/projects/")
+	projectID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return notFound("invalid project ID: %s", idStr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, err := findProjectByID(s.config, projectID)
+	if err != nil {
+		return notFound("%v", err)
+	}
+	path := project.Path
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+
+	case action == "" && r.Method == http.MethodPatch:
+		var req projectUpdateRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if req.Status != "" && !isValidProjectStatus(req.Status) {
+			return badRequest("invalid status: %s", req.Status)
+		}
+		if req.Priority != "" && !isValidPriority(req.Priority) {
+			return badRequest("invalid priority: %s (must be p1, p2, or p3)", req.Priority)
+		}
+		updates := ProjectMetadata{
+			Status:   req.Status,
+			Priority: req.Priority,
+			Area:     req.Area,
+		}
+		if updates.DueDate, err = validateDate("due_date", req.DueDate); err != nil {
+			return err
+		}
+		if updates.StartDate, err = validateDate("start_date", req.StartDate); err != nil {
+			return err
+		}
+
+		if err := checkIfMatch(r, path); err != nil {
+			return err
+		}
+		if _, err := applyProjectUpdate(s.config, path, updates, req.Tags); err != nil {
+			return err
+		}
+
+	case action == "log" && r.Method == http.MethodPost:
+		if err := s.logTo(r, path); err != nil {
+			return err
+		}
+
+	case action == "":
+		return methodNotAllowed(w, http.MethodGet, http.MethodPatch)
+	case action == "log":
+		return methodNotAllowed(w, http.MethodPost)
+	default:
+		return notFound("unknown action: %s", action)
+	}
+
+	project, err = findProjectByID(s.config, projectID)
+	if err != nil {
+		return err
+	}
+	result := projectJSON(project)
+	if result.Body, err = withBody(project.Path); err != nil {
+		return err
+	}
+	return writeResource(w, http.StatusOK, project.Path, result)
+}
+
+// logTo adds a dated log entry to any file, so projects and notes share the
+// task log format
+func (s *apiServer) logTo(r *http.Request, path string) error {
+	var req logRequest
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Entry) == "" {
+		return badRequest("entry is required")
+	}
+	if err := checkIfMatch(r, path); err != nil {
+		return err
+	}
+	return addLogEntry(s.config, path, req.Entry)
+}
+
+// Notes
+
+func noteJSON(note *Note, path string, modified time.Time) NoteJSON {
+	return NoteJSON{
+		ID:        note.ID,
+		Title:     note.Title,
+		Signature: note.Signature,
+		Tags:      note.Tags,
+		Path:      path,
+		Modified:  modified,
+	}
+}
+
+func (s *apiServer) notes(w http.ResponseWriter, r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet:
+		filters := NoteFilters{
+			Tag:     r.URL.Query().Get("tag"),
+			SortBy:  r.URL.Query().Get("sort"),
+			Reverse: queryBool(r, "reverse"),
+		}
+		notes, err := findNotes(s.config, filters)
+		if err != nil {
+			return err
+		}
+		result := []NoteJSON{}
+		for _, n := range notes {
+			result = append(result, noteJSON(n.Note, n.Path, n.ModTime))
+		}
+		writeJSON(w, http.StatusOK, result)
+		return nil
+
+	case http.MethodPost:
+		var req noteRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Title) == "" {
+			return badRequest("title is required")
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		note := Note{
+			ID:        generateDenoteID(),
+			Signature: sluggifySignature(req.Signature),
+			Title:     req.Title,
+			Tags:      req.Tags,
+		}
+		path, err := writeNewNote(s.config.NotesDir, note, req.Body)
+		if err != nil {
+			return err
+		}
+		gitAutoCommit(s.config, fmt.Sprintf("%s: create", fileLabel(path)), path)
+		return s.writeNote(w, http.StatusCreated, path)
+	}
+
+	return methodNotAllowed(w, http.MethodGet, http.MethodPost)
+}
+
+func (s *apiServer) note(w http.ResponseWriter, r *http.Request) error {
+	id, action := splitResourcePath(r.URL.Path, "/api/notes/")
+	if !isDenoteIdentifier(id) {
+		return notFound("invalid note ID: %s", id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	linked, err := findFileByDenoteID(s.config, id)
+	if err != nil {
+		return notFound("%v", err)
+	}
+
+	path := linked.Path
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "" && r.Method == http.MethodPatch:
+		var req noteUpdateRequest
+		if err := readJSON(r, &req); err != nil {
+			return err
+		}
+		note, err := noteIdentity(path)
+		if err != nil {
+			return err
+		}
+		// Rewriting a task or project as a note would drop its metadata
+		if kind := noteKind(note); kind != "note" {
+			return badRequest("%s is a %s; update it through /api/%ss", id, kind, kind)
+		}
+		if err := checkIfMatch(r, path); err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(req.Title) != "" {
+			note.Title = req.Title
+		}
+		if req.Signature != "" {
+			note.Signature = sluggifySignature(req.Signature)
+		}
+		if req.Tags != "" {
+			note.Tags = applyTagUpdates(note.Tags, parseTagUpdates(req.Tags))
+		}
+		if path, err = updateNoteFile(s.config, path, *note, req.Body); err != nil {
+			return err
+		}
+	case action == "log" && r.Method == http.MethodPost:
+		if err := s.logTo(r, path); err != nil {
+			return err
+		}
+	case action == "":
+		return methodNotAllowed(w, http.MethodGet, http.MethodPatch)
+	case action == "log":
+		return methodNotAllowed(w, http.MethodPost)
+	default:
+		return notFound("unknown action: %s", action)
+	}
+
+	return s.writeNote(w, http.StatusOK, path)
+}
+
+func (s *apiServer) writeNote(w http.ResponseWriter, status int, path string) error {
+	note, err := noteIdentity(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	result := noteJSON(note, path, info.ModTime())
+	if result.Body, err = withBody(path); err != nil {
+		return err
+	}
+	return writeResource(w, status, path, result)
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -12,15 +11,17 @@ import (
 )
 
 type TaskMetadata struct {
-	TaskID    int    `yaml:"task_id,omitempty"`
-	Status    string `yaml:"status,omitempty"`
-	Priority  string `yaml:"priority,omitempty"`
-	DueDate   string `yaml:"due_date,omitempty"`
-	StartDate string `yaml:"start_date,omitempty"`
-	Estimate  int    `yaml:"estimate,omitempty"`
-	Project   string `yaml:"project,omitempty"`
-	Area      string `yaml:"area,omitempty"`
-	Assignee  string `yaml:"assignee,omitempty"`
+	TaskID        int    `yaml:"task_id,omitempty" json:"task_id,omitempty"`
+	Status        string `yaml:"status,omitempty" json:"status,omitempty"`
+	Priority      string `yaml:"priority,omitempty" json:"priority,omitempty"`
+	DueDate       string `yaml:"due_date,omitempty" json:"due_date,omitempty"`
+	StartDate     string `yaml:"start_date,omitempty" json:"start_date,omitempty"`
+	CompletedDate string `yaml:"completed_date,omitempty" json:"completed_date,omitempty"` // set when the status becomes done
+	Estimate      int    `yaml:"estimate,omitempty" json:"estimate,omitempty"`
+	Project       string `yaml:"project,omitempty" json:"project,omitempty"`
+	Area          string `yaml:"area,omitempty" json:"area,omitempty"`
+	Assignee      string `yaml:"assignee,omitempty" json:"assignee,omitempty"`
+	Remind        string `yaml:"remind,omitempty" json:"remind,omitempty"`
 }
 
 type Task struct {
@@ -33,17 +34,20 @@ func (t Task) Frontmatter() string {
 id: "{{ .ID }}"{{ if .TaskID }}
 task_id: {{ .TaskID }}{{ end }}
 title: "{{ .Title }}"
-date: {{ .Date }}
+date: {{ .Date }}{{ if .Signature }}
+signature: "{{ .Signature }}"{{ end }}
 tags:{{ range .Tags }}
   - {{ . }}{{ end }}{{ if .Status }}
 status: {{ .Status }}{{ end }}{{ if .Priority }}
 priority: {{ .Priority }}{{ end }}{{ if .DueDate }}
 due_date: {{ .DueDate }}{{ end }}{{ if .StartDate }}
-start_date: {{ .StartDate }}{{ end }}{{ if .Estimate }}
+start_date: {{ .StartDate }}{{ end }}{{ if .CompletedDate }}
+completed_date: {{ .CompletedDate }}{{ end }}{{ if .Estimate }}
 estimate: {{ .Estimate }}{{ end }}{{ if .Project }}
 project: "{{ .Project }}"{{ end }}{{ if .Area }}
 area: "{{ .Area }}"{{ end }}{{ if .Assignee }}
-assignee: "{{ .Assignee }}"{{ end }}
+assignee: "{{ .Assignee }}"{{ end }}{{ if .Remind }}
+remind: "{{ .Remind }}"{{ end }}
 ---
 
 `
@@ -51,51 +55,80 @@ assignee: "{{ .Assignee }}"{{ end }}
 	
 	var result strings.Builder
 	tpl.Execute(&result, map[string]interface{}{
-		"ID":        t.ID,
-		"TaskID":    t.TaskID,
-		"Title":     t.Title,
-		"Date":      formatDateFromID(t.ID),
-		"Tags":      t.Tags,
-		"Status":    t.Status,
-		"Priority":  t.Priority,
-		"DueDate":   t.DueDate,
-		"StartDate": t.StartDate,
-		"Estimate":  t.Estimate,
-		"Project":   t.Project,
-		"Area":      t.Area,
-		"Assignee":  t.Assignee,
+		"ID":            t.ID,
+		"TaskID":        t.TaskID,
+		"Signature":     t.Signature,
+		"Title":         t.Title,
+		"Date":          formatDateFromID(t.ID),
+		"Tags":          t.Tags,
+		"Status":        t.Status,
+		"Priority":      t.Priority,
+		"DueDate":       t.DueDate,
+		"StartDate":     t.StartDate,
+		"CompletedDate": t.CompletedDate,
+		"Estimate":      t.Estimate,
+		"Project":       t.Project,
+		"Area":          t.Area,
+		"Assignee":      t.Assignee,
+		"Remind":        t.Remind,
 	})
 	
 	return result.String()
 }
 
-func createTask(config Config, title string, meta TaskMetadata, extraTags []string, noEdit bool) error {
+func createTask(config Config, title string, meta TaskMetadata, extraTags []string, body string, noEdit bool) error {
+	task, filepath, err := writeTask(config, title, meta, extraTags, body, noEdit)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("%s Task #%d created: %s\n", success("✓"), task.TaskID, bold(task.Title))
+	
+	// Build status line with colors
+	statusParts := []string{}
+	if task.Priority != "" {
+		statusParts = append(statusParts, priority(task.Priority))
+	}
+	if task.Area != "" {
+		statusParts = append(statusParts, fmt.Sprintf("Area: %s", area(task.Area)))
+	}
+	if task.DueDate != "" {
+		dueText := getDueDateDisplay(task.DueDate)
+		overdueFlag := isOverdue(task.DueDate)
+		statusParts = append(statusParts, fmt.Sprintf("Due: %s", due(dueText, overdueFlag)))
+	}
+	if len(statusParts) > 0 {
+		fmt.Printf("  %s\n", strings.Join(statusParts, " | "))
+	}
+	
+	fmt.Printf("  Location: %s\n", filepath)
+	fmt.Printf("→ Run 'notes-cli edit %s' to add more details\n", task.Filename())
+	return nil
+}
+
+// writeTask creates a task file and returns the task and its path. It
+// prints nothing, so the API and imports can use it.
+func writeTask(config Config, title string, meta TaskMetadata, extraTags []string, body string, noEdit bool) (*Task, string, error) {
 	// Set defaults
 	if meta.Status == "" {
 		meta.Status = "open"
 	}
-	
-	// Assign task ID if not provided
-	if meta.TaskID == 0 {
-		counter, err := getIDCounter(config)
-		if err != nil {
-			return fmt.Errorf("failed to get ID counter: %w", err)
-		}
-		taskID, err := counter.NextTask()
-		if err != nil {
-			return fmt.Errorf("failed to get next task ID: %w", err)
-		}
-		meta.TaskID = taskID
+	if meta.Status == "done" && meta.CompletedDate == "" {
+		meta.CompletedDate = time.Now().Format("2006-01-02")
 	}
 	
 	// Validate priority
 	if meta.Priority != "" && !isValidPriority(meta.Priority) {
-		return fmt.Errorf("invalid priority: %s (must be p1, p2, or p3)", meta.Priority)
+		return nil, "", invalidf("invalid priority: %s (must be p1, p2, or p3)", meta.Priority)
 	}
 	
 	// Validate estimate
 	if meta.Estimate != 0 && !isValidEstimate(meta.Estimate) {
-		return fmt.Errorf("invalid estimate: %d (must be fibonacci: 1,2,3,5,8,13)", meta.Estimate)
+		return nil, "", invalidf("invalid estimate: %d (must be fibonacci: 1,2,3,5,8,13)", meta.Estimate)
+	}
+	
+	if err := checkVocabulary(config, meta.Area, extraTags); err != nil {
+		return nil, "", err
 	}
 	
 	// Build tags - always include "task"
@@ -118,60 +151,69 @@ func createTask(config Config, title string, meta TaskMetadata, extraTags []stri
 	
 	// Check if file already exists
 	if _, err := os.Stat(filepath); err == nil {
-		return fmt.Errorf("file already exists: %s", filepath)
+		return nil, "", invalidf("file already exists: %s", filepath)
+	}
+	
+	payload := taskHookPayload(filepath, TaskFrontmatter{ID: task.ID, Title: task.Title, Tags: task.Tags, TaskMetadata: meta})
+	payload.Old = nil
+	if err := runPreHooks(config, "create", payload); err != nil {
+		return nil, "", err
 	}
 	
+	// Assign task ID if not provided, once nothing can stop the create,
+	// so a vetoed or invalid task doesn't use up an ID
+	if task.TaskID == 0 {
+		counter, err := getIDCounter(config)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get ID counter: %w", err)
+		}
+		taskID, err := counter.NextTask()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get next task ID: %w", err)
+		}
+		task.TaskID = taskID
+	}
+
 	// Create file with frontmatter
 	file, err := os.Create(filepath)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return nil, "", fmt.Errorf("failed to create file: %w", err)
 	}
 	
-	_, err = file.WriteString(task.Frontmatter())
+	_, err = file.WriteString(task.Frontmatter() + body)
 	file.Close()
 	if err != nil {
-		return fmt.Errorf("failed to write frontmatter: %w", err)
+		return nil, "", fmt.Errorf("failed to write frontmatter: %w", err)
 	}
 	
 	// Open in editor unless --no-edit flag is set
 	if !noEdit {
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi"
-		}
-		
-		cmd := exec.Command(editor, filepath)
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to open editor: %w", err)
+		if err := openInEditor(filepath); err != nil {
+			return nil, "", err
 		}
 	}
 	
-	fmt.Printf("%s Task #%d created: %s\n", success("✓"), task.TaskID, bold(task.Title))
+	gitAutoCommit(config, fmt.Sprintf("task #%d: create %q", task.TaskID, task.Title), filepath)
 	
-	// Build status line with colors
-	statusParts := []string{}
-	if task.Priority != "" {
-		statusParts = append(statusParts, priority(task.Priority))
-	}
-	if task.Area != "" {
-		statusParts = append(statusParts, fmt.Sprintf("Area: %s", area(task.Area)))
-	}
-	if task.DueDate != "" {
-		dueText := getDueDateDisplay(task.DueDate)
-		overdueFlag := isOverdue(task.DueDate)
-		statusParts = append(statusParts, fmt.Sprintf("Due: %s", due(dueText, overdueFlag)))
-	}
-	if len(statusParts) > 0 {
-		fmt.Printf("  %s\n", strings.Join(statusParts, " | "))
+	// The editor may have changed the task
+	if edited, err := fileHookPayload(filepath); err == nil {
+		payload = edited
+		payload.Old = nil
 	}
+	runPostHooks(config, "create", payload)
 	
-	fmt.Printf("  Location: %s\n", filepath)
-	fmt.Printf("→ Run 'notes-cli edit %s' to add more details\n", filename)
-	return nil
+	return &task, filepath, nil
+}
+
+// createTaskSilently creates a task without opening an editor or printing
+// anything, and returns it. Used when creating tasks for other programs
+// (imports, sync, the API).
+func createTaskSilently(config Config, title string, meta TaskMetadata, extraTags []string, body string) (*TaskInfo, error) {
+	_, path, err := writeTask(config, title, meta, extraTags, body, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseTaskFile(path)
 }
 
 func isValidPriority(p string) bool {
@@ -215,6 +257,8 @@ func parseDate(dateStr string) (string, error) {
 		targetDate = now
 	case "tomorrow":
 		targetDate = now.AddDate(0, 0, 1)
+	case "yesterday":
+		targetDate = now.AddDate(0, 0, -1)
 	case "next week":
 		targetDate = now.AddDate(0, 0, 7)
 	case "next month":
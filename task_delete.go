@@ -40,11 +40,23 @@ func deleteTask(config Config, taskRef string) error {
 		return nil
 	}
 	
+	payload, err := fileHookPayload(taskFile)
+	if err != nil {
+		return fmt.Errorf("failed to read task: %w", err)
+	}
+	payload.New = nil
+	if err := runPreHooks(config, "delete", payload); err != nil {
+		return err
+	}
+	
 	// Delete the file
 	if err := os.Remove(taskFile); err != nil {
 		return fmt.Errorf("failed to delete task file: %w", err)
 	}
 	
+	gitAutoCommit(config, fmt.Sprintf("task #%d: delete %q", taskInfo.TaskID, taskInfo.Note.Title), taskFile)
+	runPostHooks(config, "delete", payload)
+	
 	fmt.Printf("Task %d deleted successfully.\n", taskInfo.TaskID)
 	return nil
 }
@@ -112,14 +124,34 @@ func deleteTasks(config Config, taskArgs string) error {
 	
 	// Delete all confirmed tasks
 	successCount := 0
+	var deleted, deletedIDs []string
+	var payloads []HookPayload
 	for _, task := range tasks {
+		payload, err := fileHookPayload(task.File)
+		if err == nil {
+			payload.New = nil
+			err = runPreHooks(config, "delete", payload)
+		}
+		if err != nil {
+			fmt.Printf("Failed to delete task %d: %v\n", task.ID, err)
+			continue
+		}
+		
 		if err := os.Remove(task.File); err != nil {
 			fmt.Printf("Failed to delete task %d: %v\n", task.ID, err)
 		} else {
 			successCount++
+			deleted = append(deleted, task.File)
+			deletedIDs = append(deletedIDs, fmt.Sprintf("#%d", task.ID))
+			payloads = append(payloads, payload)
 		}
 	}
 	
+	gitAutoCommit(config, fmt.Sprintf("tasks %s: delete", strings.Join(deletedIDs, ", ")), deleted...)
+	for _, payload := range payloads {
+		runPostHooks(config, "delete", payload)
+	}
+	
 	fmt.Printf("\nDeleted %d of %d tasks.\n", successCount, len(tasks))
 	return nil
 }
\ No newline at end of file
@@ -15,6 +15,17 @@ func logToTask(config Config, taskRef string, logEntry string) error {
 		return err
 	}
 
+	if err := addLogEntry(config, taskPath, logEntry); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added log entry to task: %s\n", taskPath)
+	return nil
+}
+
+// addLogEntry adds a dated log entry after the frontmatter of any file
+// without printing anything
+func addLogEntry(config Config, taskPath string, logEntry string) error {
 	// Read the existing file
 	file, err := os.Open(taskPath)
 	if err != nil {
@@ -53,6 +64,15 @@ func logToTask(config Config, taskRef string, logEntry string) error {
 		return fmt.Errorf("no YAML frontmatter found in task file")
 	}
 	
+	payload, err := fileHookPayload(taskPath)
+	if err != nil {
+		return fmt.Errorf("failed to read task: %w", err)
+	}
+	payload.Log = logEntry
+	if err := runPreHooks(config, "log", payload); err != nil {
+		return err
+	}
+	
 	// Create the log entry with current date
 	currentDate := time.Now().Format("2006-01-02")
 	logLine := fmt.Sprintf("[%s] %s", currentDate, logEntry)
@@ -95,6 +115,7 @@ func logToTask(config Config, taskRef string, logEntry string) error {
 		return fmt.Errorf("failed to write updated file: %w", err)
 	}
 	
-	fmt.Printf("Added log entry to task: %s\n", taskPath)
+	gitAutoCommit(config, fmt.Sprintf("%s: log %q", fileLabel(taskPath), commitSubject(logEntry)), taskPath)
+	runPostHooks(config, "log", payload)
 	return nil
 }
\ No newline at end of file
@@ -11,7 +11,7 @@ import (
 func findTaskByID(config Config, taskID int) (*TaskInfo, error) {
 	// Get all task files
 	pattern := filepath.Join(config.TaskDir, "*__task*.md")
-	files, err := filepath.Glob(pattern)
+	files, err := globNotes(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list task files: %w", err)
 	}
@@ -19,7 +19,7 @@ func findTaskByID(config Config, taskID int) (*TaskInfo, error) {
 	// Also check notes directory if different
 	if config.TaskDir != config.NotesDir {
 		notesPattern := filepath.Join(config.NotesDir, "*__task*.md")
-		notesFiles, err := filepath.Glob(notesPattern)
+		notesFiles, err := globNotes(notesPattern)
 		if err == nil {
 			files = append(files, notesFiles...)
 		}
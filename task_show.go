@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// showTask prints a task's metadata, body and links
+func showTask(config Config, arg string) error {
+	taskPath, err := resolveTaskArg(config, arg)
+	if err != nil {
+		return err
+	}
+
+	task, err := parseTaskFile(taskPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse task: %w", err)
+	}
+
+	// Header
+	if task.TaskID > 0 {
+		fmt.Printf("%s %s\n", bold(fmt.Sprintf("#%d", task.TaskID)), bold(task.Note.Title))
+	} else {
+		fmt.Println(bold(task.Note.Title))
+	}
+
+	// Status line
+	statusParts := []string{fmt.Sprintf("%s %s", status(task.Status), task.Status)}
+	if task.Priority != "" {
+		statusParts = append(statusParts, priority(task.Priority))
+	}
+	if task.DueDate != "" {
+		dueText := getDueDateDisplay(task.DueDate)
+		statusParts = append(statusParts, fmt.Sprintf("Due: %s", due(dueText, isOverdue(task.DueDate))))
+	}
+	if task.StartDate != "" {
+		statusParts = append(statusParts, fmt.Sprintf("Start: %s", task.StartDate))
+	}
+	if task.Estimate > 0 {
+		statusParts = append(statusParts, estimate(task.Estimate))
+	}
+	fmt.Printf("  %s\n", strings.Join(statusParts, " | "))
+
+	detailParts := []string{}
+	if task.Project != "" {
+		detailParts = append(detailParts, fmt.Sprintf("Project: %s", project(task.Project)))
+	}
+	if task.Area != "" {
+		detailParts = append(detailParts, fmt.Sprintf("Area: %s", area(task.Area)))
+	}
+	if task.Assignee != "" {
+		detailParts = append(detailParts, fmt.Sprintf("Assignee: %s", task.Assignee))
+	}
+	if task.Remind != "" {
+		detailParts = append(detailParts, fmt.Sprintf("Remind: %s", task.Remind))
+	}
+	if len(detailParts) > 0 {
+		fmt.Printf("  %s\n", strings.Join(detailParts, " | "))
+	}
+
+	if len(task.Note.Tags) > 0 {
+		coloredTags := make([]string, len(task.Note.Tags))
+		for i, t := range task.Note.Tags {
+			coloredTags[i] = tag(t)
+		}
+		fmt.Printf("  Tags: %s\n", strings.Join(coloredTags, ", "))
+	}
+	fmt.Printf("  %s %s\n", dim("Location:"), filename(taskPath))
+
+	// Body
+	body, err := readNoteBody(taskPath)
+	if err != nil {
+		return fmt.Errorf("failed to read task: %w", err)
+	}
+	if body = strings.TrimRight(body, "\n"); body != "" {
+		fmt.Printf("\n%s\n", body)
+	}
+
+	// Links
+	links, err := findOutgoingLinks(config, taskPath)
+	if err != nil {
+		return fmt.Errorf("failed to read links: %w", err)
+	}
+
+	var backlinks []LinkedFile
+	if task.Note.ID != "" {
+		backlinks, err = findBacklinks(config, task.Note.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read backlinks: %w", err)
+		}
+	}
+
+	if len(links) > 0 || len(backlinks) > 0 {
+		fmt.Printf("\n%s\n", bold("Links:"))
+		for _, link := range links {
+			if link.Path == "" {
+				fmt.Printf("  → %s %s\n", warning("(missing)"), date("("+link.ID+")"))
+				continue
+			}
+			fmt.Printf("  → %s %s %s\n", linkKind(link.Kind), link.Title, date("("+link.ID+")"))
+		}
+		for _, link := range backlinks {
+			fmt.Printf("  ← %s %s %s\n", linkKind(link.Kind), link.Title, date("("+link.ID+")"))
+		}
+	}
+
+	fmt.Println()
+	return nil
+}
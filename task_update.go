@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,10 +17,47 @@ func updateTask(config Config, arg string, updates TaskMetadata, tagUpdates stri
 		return err
 	}
 	
+	newPath, err := applyTaskUpdate(config, notePath, updates, tagUpdates)
+	if err != nil {
+		return err
+	}
+	
+	// Show success message based on what was updated
+	if updates.Status == "done" {
+		fmt.Println(success("✓") + " Task marked as done!")
+	} else if updates.Status != "" {
+		fmt.Printf("%s Task status changed to: %s\n", success("✓"), updates.Status)
+	} else {
+		fmt.Printf("%s Task updated successfully\n", success("✓"))
+	}
+	fmt.Printf("  %s %s\n", dim("Location:"), filename(notePath))
+	if newPath != notePath {
+		fmt.Printf("Renamed to: %s\n", newPath)
+	}
+	
+	return nil
+}
+
+// applyTaskUpdate updates the task at notePath without printing anything
+// and returns its (possibly new) path
+func applyTaskUpdate(config Config, notePath string, updates TaskMetadata, tagUpdates string) (string, error) {
+	if updates.Status != "" && !isValidStatus(updates.Status) {
+		return "", invalidf("invalid status: %s (must be open, done, paused, delegated, or dropped)", updates.Status)
+	}
+	if updates.Priority != "" && !isValidPriority(updates.Priority) {
+		return "", invalidf("invalid priority: %s (must be p1, p2, or p3)", updates.Priority)
+	}
+	if updates.Estimate != 0 && !isValidEstimate(updates.Estimate) {
+		return "", invalidf("invalid estimate: %d (must be fibonacci: 1,2,3,5,8,13)", updates.Estimate)
+	}
+	if err := checkRemind(updates.Remind); err != nil {
+		return "", &invalidError{err}
+	}
+	
 	// Read the file
 	content, err := os.ReadFile(notePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 	
 	// Parse frontmatter and content
@@ -41,15 +79,17 @@ func updateTask(config Config, arg string, updates TaskMetadata, tagUpdates stri
 	}
 	
 	if frontmatterStart == -1 || frontmatterEnd == -1 {
-		return fmt.Errorf("no frontmatter found in file")
+		return "", fmt.Errorf("no frontmatter found in file")
 	}
 	
 	// Parse existing frontmatter
 	yamlContent := strings.Join(lines[frontmatterStart+1:frontmatterEnd], "\n")
 	var fm TaskFrontmatter
 	if err := yaml.Unmarshal([]byte(yamlContent), &fm); err != nil {
-		return fmt.Errorf("failed to parse frontmatter: %w", err)
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	before := fm
+	before.Tags = append([]string(nil), fm.Tags...)
 	
 	// Apply updates
 	if updates.Status != "" {
@@ -76,6 +116,16 @@ func updateTask(config Config, arg string, updates TaskMetadata, tagUpdates stri
 	if updates.Assignee != "" {
 		fm.Assignee = updates.Assignee
 	}
+	if updates.Remind != "" {
+		fm.Remind = updates.Remind
+	}
+	
+	fm.trackCompletion(before.Status)
+	
+	// Keep a signature that only exists in the filename
+	if fm.Signature == "" {
+		fm.Signature = signatureFromFilename(notePath)
+	}
 	
 	// Apply tag updates
 	if tagUpdates != "" {
@@ -83,16 +133,35 @@ func updateTask(config Config, arg string, updates TaskMetadata, tagUpdates stri
 		fm.Tags = applyTagUpdates(fm.Tags, tagUpdate)
 	}
 	
+	// Only check the tags being added, so existing ones can still be removed
+	var addedTags []string
+	for _, tag := range fm.Tags {
+		if !containsTag(before.Tags, tag) {
+			addedTags = append(addedTags, tag)
+		}
+	}
+	if err := checkVocabulary(config, updates.Area, addedTags); err != nil {
+		return "", err
+	}
+	
 	// Create updated task for frontmatter generation
 	task := Task{
 		Note: Note{
-			ID:    fm.ID,
-			Title: fm.Title,
-			Tags:  fm.Tags,
+			ID:        fm.ID,
+			Signature: fm.Signature,
+			Title:     fm.Title,
+			Tags:      fm.Tags,
 		},
 		TaskMetadata: fm.TaskMetadata,
 	}
 	
+	action := updateAction(before.Status, fm.Status)
+	payload := taskHookPayload(notePath, fm)
+	payload.Old = &before.TaskMetadata
+	if err := runPreHooks(config, action, payload); err != nil {
+		return "", err
+	}
+	
 	// Generate new frontmatter
 	newFrontmatter := task.Frontmatter()
 	
@@ -108,34 +177,110 @@ func updateTask(config Config, arg string, updates TaskMetadata, tagUpdates stri
 	
 	// Write back to file
 	if err := os.WriteFile(notePath, []byte(strings.Join(newContent, "\n")), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-	
-	// Show success message based on what was updated
-	if updates.Status == "done" {
-		fmt.Println(success("✓") + " Task marked as done!")
-	} else if updates.Status != "" {
-		fmt.Printf("%s Task status changed to: %s\n", success("✓"), updates.Status)
-	} else {
-		fmt.Printf("%s Task updated successfully\n", success("✓"))
+		return "", fmt.Errorf("failed to write file: %w", err)
 	}
-	fmt.Printf("  %s %s\n", dim("Location:"), filename(notePath))
 	
 	// If title changed, might need to rename file
 	oldFilename := filepath.Base(notePath)
 	newFilename := task.Filename()
+	newPath := notePath
 	
 	if oldFilename != newFilename {
-		newPath := filepath.Join(filepath.Dir(notePath), newFilename)
+		newPath = filepath.Join(filepath.Dir(notePath), newFilename)
 		if err := os.Rename(notePath, newPath); err != nil {
-			return fmt.Errorf("failed to rename file: %w", err)
+			return "", fmt.Errorf("failed to rename file: %w", err)
 		}
-		fmt.Printf("Renamed to: %s\n", newPath)
 	}
 	
-	return nil
+	recordStatusChange(config, fm.ID, before.Status, fm.Status)
+	message := fmt.Sprintf("task #%d: %s", fm.TaskID, describeTaskChanges(before, fm))
+	gitAutoCommit(config, message, notePath, newPath)
+	
+	payload.Path = newPath
+	runPostHooks(config, action, payload)
+	
+	return newPath, nil
+}
+
+// trackCompletion sets completed_date when a task becomes done and clears
+// it when the task is reopened
+func (m *TaskMetadata) trackCompletion(before string) {
+	if m.Status != "done" {
+		m.CompletedDate = ""
+	} else if before != "done" {
+		m.CompletedDate = time.Now().Format("2006-01-02")
+	}
 }
 
 func markTaskDone(config Config, arg string) error {
 	return updateTask(config, arg, TaskMetadata{Status: "done"}, "")
-}
\ No newline at end of file
+}
+
+// rewriteTask applies mutate to a task's frontmatter and writes it back,
+// renaming the file if its name changes. Unlike updateTask it can clear
+// fields. It runs hooks and commits like updateTask, and returns the task's
+// (possibly new) path.
+func rewriteTask(config Config, notePath string, mutate func(fm *TaskFrontmatter)) (string, error) {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	
+	frontmatter, body := splitFrontmatter(string(content))
+	if frontmatter == "" {
+		return "", fmt.Errorf("no frontmatter found in file")
+	}
+	
+	var fm TaskFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	
+	// Keep a signature that only exists in the filename
+	if fm.Signature == "" {
+		fm.Signature = signatureFromFilename(notePath)
+	}
+	before := fm
+	before.Tags = append([]string(nil), fm.Tags...)
+	
+	mutate(&fm)
+	fm.trackCompletion(before.Status)
+	
+	task := Task{
+		Note: Note{
+			ID:        fm.ID,
+			Signature: fm.Signature,
+			Title:     fm.Title,
+			Tags:      fm.Tags,
+		},
+		TaskMetadata: fm.TaskMetadata,
+	}
+	
+	action := updateAction(before.Status, fm.Status)
+	payload := taskHookPayload(notePath, fm)
+	payload.Old = &before.TaskMetadata
+	if err := runPreHooks(config, action, payload); err != nil {
+		return "", err
+	}
+	
+	newContent := strings.TrimRight(task.Frontmatter(), "\n") + "\n" + body
+	if err := os.WriteFile(notePath, []byte(newContent), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	
+	newPath := filepath.Join(filepath.Dir(notePath), task.Filename())
+	if newPath != notePath {
+		if err := os.Rename(notePath, newPath); err != nil {
+			return "", fmt.Errorf("failed to rename file: %w", err)
+		}
+	}
+	
+	recordStatusChange(config, fm.ID, before.Status, fm.Status)
+	message := fmt.Sprintf("task #%d: %s", fm.TaskID, describeTaskChanges(before, fm))
+	gitAutoCommit(config, message, notePath, newPath)
+	
+	payload.Path = newPath
+	runPostHooks(config, action, payload)
+	
+	return newPath, nil
+}
@@ -17,11 +17,22 @@ type TaskInfo struct {
 	TaskMetadata
 }
 
+// completedOn is the day a task was finished: its completed_date, or for
+// tasks finished before completed_date was recorded, the day its file last
+// changed
+func (t TaskInfo) completedOn() string {
+	if t.CompletedDate != "" {
+		return t.CompletedDate
+	}
+	return t.ModTime.Format("2006-01-02")
+}
+
 type TaskFrontmatter struct {
-	ID    string   `yaml:"id"`
-	Title string   `yaml:"title"`
-	Date  string   `yaml:"date"`
-	Tags  []string `yaml:"tags"`
+	ID        string   `yaml:"id"`
+	Signature string   `yaml:"signature"`
+	Title     string   `yaml:"title"`
+	Date      string   `yaml:"date"`
+	Tags      []string `yaml:"tags"`
 	TaskMetadata `yaml:",inline"`
 }
 
@@ -32,26 +43,65 @@ func listTasks(config Config, filters TaskFilters) error {
 		filters.Status = "open"
 	}
 	
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+	
+	if tasks == nil {
+		fmt.Println("No tasks found")
+		return nil
+	}
+	
+	// Display results
+	displayTasks(tasks, filters)
+	
+	// Save index cache for task operations
+	saveTaskIndexCache(config, tasks)
+	
+	return nil
+}
+
+// printTasksJSON prints the tasks 'task list' would show, as JSON
+func printTasksJSON(config Config, filters TaskFilters) error {
+	if !filters.All && filters.Status == "" {
+		filters.Status = "open"
+	}
+	
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+	
+	result := []TaskJSON{}
+	for i := range tasks {
+		result = append(result, taskJSON(&tasks[i]))
+	}
+	return printJSON(result)
+}
+
+// findTasks returns the tasks matching filters, sorted and indexed.
+// It returns nil when there are no task files at all.
+func findTasks(config Config, filters TaskFilters) ([]TaskInfo, error) {
 	// Get all markdown files with __task in the filename
 	pattern := filepath.Join(config.TaskDir, "*__task*.md")
-	files, err := filepath.Glob(pattern)
+	files, err := globNotes(pattern)
 	if err != nil {
-		return fmt.Errorf("failed to list task files: %w", err)
+		return nil, fmt.Errorf("failed to list task files: %w", err)
 	}
 	
 	if len(files) == 0 {
-		fmt.Println("No tasks found")
-		return nil
+		return nil, nil
 	}
 	
 	// Parse each file to get task details
-	var tasks []TaskInfo
+	tasks := []TaskInfo{}
 	for _, file := range files {
 		if file == "" {
 			continue
 		}
 		
-		taskInfo, err := parseTaskFile(file)
+		taskInfo, err := cachedTaskFile(file)
 		if err != nil {
 			continue
 		}
@@ -61,44 +111,7 @@ func listTasks(config Config, filters TaskFilters) error {
 			continue
 		}
 		
-		// Apply status filter
-		if filters.Status != "" && taskInfo.Status != filters.Status {
-			continue
-		}
-		
-		// Apply priority filter
-		if filters.Priority != "" && taskInfo.Priority != filters.Priority {
-			continue
-		}
-		
-		// Apply project filter (case-insensitive)
-		if filters.Project != "" {
-			if !strings.EqualFold(taskInfo.Project, filters.Project) {
-				continue
-			}
-		}
-		
-		// Apply area filter
-		if filters.Area != "" && taskInfo.Area != filters.Area {
-			continue
-		}
-		
-		// Apply tag filter
-		if filters.Tag != "" && !hasTag(taskInfo.Note.Tags, filters.Tag) {
-			continue
-		}
-		
-		// Apply additional filters
-		if filters.Overdue && !isOverdue(taskInfo.DueDate) {
-			continue
-		}
-		
-		if filters.DueFilter != "" && !matchesDueFilter(taskInfo.DueDate, filters.DueFilter) {
-			continue
-		}
-		
-		// Apply soon filter
-		if filters.SoonDays > 0 && !isDueSoon(taskInfo.DueDate, filters.SoonDays) {
+		if !matchesTaskFilters(taskInfo, filters) {
 			continue
 		}
 		
@@ -113,13 +126,53 @@ func listTasks(config Config, filters TaskFilters) error {
 		tasks[i].Index = i + 1
 	}
 	
-	// Display results
-	displayTasks(tasks, filters)
+	return tasks, nil
+}
+
+// matchesTaskFilters reports whether a task passes every filter that is set
+func matchesTaskFilters(taskInfo *TaskInfo, filters TaskFilters) bool {
+	// Apply status filter
+	if filters.Status != "" && taskInfo.Status != filters.Status {
+		return false
+	}
 	
-	// Save index cache for task operations
-	saveTaskIndexCache(config, tasks)
+	// Apply priority filter
+	if filters.Priority != "" && taskInfo.Priority != filters.Priority {
+		return false
+	}
 	
-	return nil
+	// Apply project filter (case-insensitive)
+	if filters.Project != "" {
+		if !strings.EqualFold(taskInfo.Project, filters.Project) {
+			return false
+		}
+	}
+	
+	// Apply area filter
+	if filters.Area != "" && taskInfo.Area != filters.Area {
+		return false
+	}
+	
+	// Apply tag filter
+	if filters.Tag != "" && !hasTag(taskInfo.Note.Tags, filters.Tag) {
+		return false
+	}
+	
+	// Apply additional filters
+	if filters.Overdue && !isOverdue(taskInfo.DueDate) {
+		return false
+	}
+	
+	if filters.DueFilter != "" && !matchesDueFilter(taskInfo.DueDate, filters.DueFilter) {
+		return false
+	}
+	
+	// Apply soon filter
+	if filters.SoonDays > 0 && !isDueSoon(taskInfo.DueDate, filters.SoonDays) {
+		return false
+	}
+	
+	return true
 }
 
 func parseTaskFile(filePath string) (*TaskInfo, error) {
@@ -170,9 +223,10 @@ func parseTaskFile(filePath string) (*TaskInfo, error) {
 			Filename: filepath.Base(filePath),
 			Path:     filePath,
 			Note: &Note{
-				ID:    fm.ID,
-				Title: fm.Title,
-				Tags:  fm.Tags,
+				ID:        fm.ID,
+				Signature: fm.Signature,
+				Title:     fm.Title,
+				Tags:      fm.Tags,
 			},
 			ModTime: info.ModTime(),
 		},
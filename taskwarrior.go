@@ -0,0 +1,488 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// twTimeFormat is Taskwarrior's UTC timestamp format
+const twTimeFormat = "20060102T150405Z"
+
+// twWaitForever is the wait date of exported paused tasks: Taskwarrior has no
+// paused status, so they wait until a date that never comes
+const twWaitForever = "99991230T000000Z"
+
+// TaskwarriorTask is a task in Taskwarrior's JSON export format. area,
+// assignee and estimate are not Taskwarrior attributes; they are exported
+// as UDAs so a round trip keeps them.
+type TaskwarriorTask struct {
+	UUID        string                  `json:"uuid"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Entry       string                  `json:"entry,omitempty"`
+	Modified    string                  `json:"modified,omitempty"`
+	End         string                  `json:"end,omitempty"`
+	Due         string                  `json:"due,omitempty"`
+	Scheduled   string                  `json:"scheduled,omitempty"`
+	Wait        string                  `json:"wait,omitempty"`
+	Priority    string                  `json:"priority,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Annotations []TaskwarriorAnnotation `json:"annotations,omitempty"`
+	Area        string                  `json:"area,omitempty"`
+	Assignee    string                  `json:"assignee,omitempty"`
+	Estimate    int                     `json:"estimate,omitempty"`
+}
+
+type TaskwarriorAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// taskwarriorMap remembers which Taskwarrior UUID each imported task came
+// from, so repeat imports skip it and exports keep the UUID
+type taskwarriorMap struct {
+	UUIDs map[string]string `json:"uuids"` // Taskwarrior UUID -> Denote ID
+}
+
+func taskwarriorMapPath(config Config) string {
+	return filepath.Join(config.TaskDir, ".notes-cli-taskwarrior.json")
+}
+
+func loadTaskwarriorMap(config Config) (*taskwarriorMap, error) {
+	m := &taskwarriorMap{UUIDs: make(map[string]string)}
+
+	data, err := os.ReadFile(taskwarriorMapPath(config))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read Taskwarrior map: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", taskwarriorMapPath(config), err)
+	}
+	if m.UUIDs == nil {
+		m.UUIDs = make(map[string]string)
+	}
+
+	return m, nil
+}
+
+func (m *taskwarriorMap) save(config Config) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Taskwarrior map: %w", err)
+	}
+	if err := os.WriteFile(taskwarriorMapPath(config), data, 0644); err != nil {
+		return fmt.Errorf("failed to write Taskwarrior map: %w", err)
+	}
+	return nil
+}
+
+// uuidFor returns the UUID a task was imported with, or a stable one derived
+// from its Denote identifier
+func (m *taskwarriorMap) uuidFor(denoteID string) string {
+	for uuid, id := range m.UUIDs {
+		if id == denoteID {
+			return uuid
+		}
+	}
+	return denoteUUID(denoteID)
+}
+
+// denoteUUID derives a name-based (version 5 style) UUID from an identifier
+func denoteUUID(denoteID string) string {
+	sum := sha1.Sum([]byte("notes-cli:" + denoteID))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+func taskwarriorPriority(p string) string {
+	switch p {
+	case "p1":
+		return "H"
+	case "p2":
+		return "M"
+	case "p3":
+		return "L"
+	}
+	return ""
+}
+
+func priorityFromTaskwarrior(p string) string {
+	switch strings.ToUpper(p) {
+	case "H":
+		return "p1"
+	case "M":
+		return "p2"
+	case "L":
+		return "p3"
+	}
+	return ""
+}
+
+func taskwarriorStatus(status string) string {
+	switch status {
+	case "done":
+		return "completed"
+	case "dropped":
+		return "deleted"
+	case "paused":
+		return "waiting"
+	}
+	return "pending"
+}
+
+// statusFromTaskwarrior maps a status back. Newer Taskwarrior versions
+// export waiting tasks as pending with a wait date still to come.
+func statusFromTaskwarrior(status, wait string) string {
+	switch status {
+	case "completed":
+		return "done"
+	case "deleted":
+		return "dropped"
+	case "waiting":
+		return "paused"
+	}
+	if t, err := time.Parse(twTimeFormat, wait); err == nil && t.After(time.Now()) {
+		return "paused"
+	}
+	return "open"
+}
+
+// taskwarriorDate converts a Taskwarrior timestamp to a local YYYY-MM-DD date
+func taskwarriorDate(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(twTimeFormat, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q", value)
+	}
+	return t.Local().Format("2006-01-02"), nil
+}
+
+// taskwarriorTime converts a YYYY-MM-DD date to a Taskwarrior timestamp at
+// local midnight
+func taskwarriorTime(date string) string {
+	t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(twTimeFormat)
+}
+
+// decodeTaskwarrior reads a JSON array (task export) or one JSON object per
+// line (older versions)
+func decodeTaskwarrior(data []byte) ([]TaskwarriorTask, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var tasks []TaskwarriorTask
+		if err := json.Unmarshal(trimmed, &tasks); err != nil {
+			return nil, fmt.Errorf("invalid Taskwarrior JSON: %w", err)
+		}
+		return tasks, nil
+	}
+
+	var tasks []TaskwarriorTask
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var task TaskwarriorTask
+		if err := dec.Decode(&task); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid Taskwarrior JSON: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func readImportFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// importCommitMessage is the message of the single commit an import makes
+func importCommitMessage(path string, imported int) string {
+	source := filepath.Base(path)
+	if path == "-" {
+		source = "stdin"
+	}
+	return fmt.Sprintf("import %d %s from %s", imported, pluralize(imported, "task", "tasks"), source)
+}
+
+func importTaskwarrior(config Config, path string, dryRun bool) error {
+	data, err := readImportFile(path)
+	if err != nil {
+		return err
+	}
+	twTasks, err := decodeTaskwarrior(data)
+	if err != nil {
+		return err
+	}
+
+	uuidMap, err := loadTaskwarriorMap(config)
+	if err != nil {
+		return err
+	}
+
+	// Tasks exported from here carry UUIDs derived from their identifiers
+	existing := make(map[string]bool)
+	for uuid := range uuidMap.UUIDs {
+		existing[uuid] = true
+	}
+	tasks, err := findTasks(config, TaskFilters{All: true})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		existing[denoteUUID(task.Note.ID)] = true
+	}
+
+	if dryRun {
+		fmt.Println(dim("Dry run: nothing will be changed"))
+	}
+
+	imported, skipped, recurring := 0, 0, 0
+	var problems []string
+	gitStartBatch()
+	for i, tw := range twTasks {
+		label := fmt.Sprintf("task %d (%s)", i+1, tw.Description)
+
+		if tw.Status == "recurring" {
+			// The template of a recurring task; its instances are imported
+			recurring++
+			continue
+		}
+		if strings.TrimSpace(tw.Description) == "" {
+			problems = append(problems, fmt.Sprintf("task %d: no description", i+1))
+			continue
+		}
+		if tw.UUID != "" && existing[tw.UUID] {
+			skipped++
+			continue
+		}
+
+		meta := TaskMetadata{
+			Status:   statusFromTaskwarrior(tw.Status, tw.Wait),
+			Priority: priorityFromTaskwarrior(tw.Priority),
+			Project:  tw.Project,
+			Area:     tw.Area,
+			Assignee: tw.Assignee,
+		}
+		if isValidEstimate(tw.Estimate) {
+			meta.Estimate = tw.Estimate
+		}
+		if meta.DueDate, err = taskwarriorDate(tw.Due); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: due: %v", label, err))
+			continue
+		}
+		if meta.StartDate, err = taskwarriorDate(tw.Scheduled); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: scheduled: %v", label, err))
+			continue
+		}
+		if meta.Status == "done" {
+			// An unreadable end date falls back to the import date
+			meta.CompletedDate, _ = taskwarriorDate(tw.End)
+		}
+
+		var tags []string
+		for _, t := range tw.Tags {
+			if tag := sluggifyKeyword(t); tag != "" && tag != "task" && !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		body, err := annotationsToLog(tw.Annotations)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("  %s %s\n", info("+"), tw.Description)
+			imported++
+			continue
+		}
+
+		task, err := createTaskSilently(config, tw.Description, meta, tags, body)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+		fmt.Printf("  %s #%d %s\n", info("+"), task.TaskID, tw.Description)
+		imported++
+
+		if tw.UUID != "" {
+			uuidMap.UUIDs[tw.UUID] = task.Note.ID
+			existing[tw.UUID] = true
+			// Saved after every task, so the ones already created stay
+			// mapped if the import stops partway
+			if err := uuidMap.save(config); err != nil {
+				gitFinishBatch(config, importCommitMessage(path, imported))
+				return err
+			}
+		}
+	}
+	gitFinishBatch(config, importCommitMessage(path, imported))
+
+	fmt.Printf("%s Imported %s", success("✓"), count(imported, pluralize(imported, "task", "tasks")))
+	if skipped > 0 {
+		fmt.Printf(", skipped %d already imported", skipped)
+	}
+	if recurring > 0 {
+		fmt.Printf(", skipped %d recurring %s", recurring, pluralize(recurring, "template", "templates"))
+	}
+	fmt.Println()
+
+	if len(problems) > 0 {
+		fmt.Printf("%s %d %s could not be imported:\n", warning("!"), len(problems), pluralize(len(problems), "task", "tasks"))
+		for _, problem := range problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+
+	return nil
+}
+
+// annotationsToLog renders annotations as task log lines, newest first like
+// 'task log' adds them
+func annotationsToLog(annotations []TaskwarriorAnnotation) (string, error) {
+	sorted := append([]TaskwarriorAnnotation(nil), annotations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Entry > sorted[j].Entry
+	})
+
+	var lines []string
+	for _, a := range sorted {
+		day, err := taskwarriorDate(a.Entry)
+		if err != nil {
+			return "", fmt.Errorf("annotation: %v", err)
+		}
+		if day == "" {
+			day = time.Now().Format("2006-01-02")
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", day, strings.TrimSpace(a.Description)))
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n\n") + "\n", nil
+}
+
+// logLinePattern matches the entries written by 'task log'
+var logLinePattern = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2})\] (.+)$`)
+
+// logToAnnotations turns task log lines back into annotations
+func logToAnnotations(body string) []TaskwarriorAnnotation {
+	var annotations []TaskwarriorAnnotation
+	for _, line := range strings.Split(body, "\n") {
+		m := logLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		annotations = append(annotations, TaskwarriorAnnotation{
+			Entry:       taskwarriorTime(m[1]),
+			Description: m[2],
+		})
+	}
+
+	// Taskwarrior lists annotations oldest first
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].Entry < annotations[j].Entry
+	})
+	return annotations
+}
+
+func exportTaskwarrior(config Config, filters TaskFilters, output string) error {
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+
+	uuidMap, err := loadTaskwarriorMap(config)
+	if err != nil {
+		return err
+	}
+
+	result := []TaskwarriorTask{}
+	for _, task := range tasks {
+		tw := TaskwarriorTask{
+			UUID:        uuidMap.uuidFor(task.Note.ID),
+			Description: task.Note.Title,
+			Status:      taskwarriorStatus(task.Status),
+			Modified:    task.ModTime.UTC().Format(twTimeFormat),
+			Priority:    taskwarriorPriority(task.Priority),
+			Project:     task.Project,
+			Area:        task.Area,
+			Assignee:    task.Assignee,
+			Estimate:    task.Estimate,
+		}
+		if created, err := time.ParseInLocation(denoteIDFormat, task.Note.ID, time.Local); err == nil {
+			tw.Entry = created.UTC().Format(twTimeFormat)
+		}
+		if tw.Status == "waiting" {
+			tw.Wait = twWaitForever
+		}
+		if tw.Status == "completed" || tw.Status == "deleted" {
+			// Tasks done before completed_date existed use the last modification
+			tw.End = tw.Modified
+			if task.CompletedDate != "" {
+				tw.End = taskwarriorTime(task.CompletedDate)
+			}
+		}
+		if task.DueDate != "" {
+			tw.Due = taskwarriorTime(task.DueDate)
+		}
+		if task.StartDate != "" {
+			tw.Scheduled = taskwarriorTime(task.StartDate)
+		}
+		for _, tag := range task.Note.Tags {
+			if tag != "task" {
+				tw.Tags = append(tw.Tags, tag)
+			}
+		}
+		if body, err := readNoteBody(task.Path); err == nil {
+			tw.Annotations = logToAnnotations(body)
+		}
+
+		result = append(result, tw)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tasks: %w", err)
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", success("✓"), count(len(result), pluralize(len(result), "task", "tasks")), output)
+	fmt.Printf("→ Load it with: task import %s\n", output)
+
+	return nil
+}
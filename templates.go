@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Templates live in ~/.config/notes-cli/templates/<name>.md. The optional
+// frontmatter holds default values; the body is a Go template:
+//
+//	---
+//	priority: p2
+//	area: work
+//	tags: [bug]
+//	---
+//	## Steps to reproduce
+//
+//	Reported by {{ prompt "Reporter" }} on {{ .Date }}
+type NoteTemplate struct {
+	Name     string
+	Defaults TemplateDefaults
+	Body     string
+}
+
+// TemplateDefaults are frontmatter values applied when the command line
+// doesn't set them
+type TemplateDefaults struct {
+	Tags      []string `yaml:"tags"`
+	Status    string   `yaml:"status"`
+	Priority  string   `yaml:"priority"`
+	DueDate   string   `yaml:"due_date"`
+	StartDate string   `yaml:"start_date"`
+	Estimate  int      `yaml:"estimate"`
+	Project   string   `yaml:"project"`
+	Area      string   `yaml:"area"`
+	Assignee  string   `yaml:"assignee"`
+}
+
+// TemplateData is available to template bodies
+type TemplateData struct {
+	Type     string
+	Title    string
+	Date     string
+	Tags     []string
+	Priority string
+	DueDate  string
+	Project  string
+	Area     string
+}
+
+func templatesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "notes-cli", "templates")
+}
+
+func loadTemplate(name string) (*NoteTemplate, error) {
+	dir := templatesDir()
+	if dir == "" {
+		return nil, fmt.Errorf("cannot locate templates directory")
+	}
+
+	path := filepath.Join(dir, strings.TrimSuffix(name, ".md")+".md")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("template not found: %s (looked in %s)", name, dir)
+		}
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tpl := &NoteTemplate{Name: name}
+	frontmatter, body := splitFrontmatter(string(content))
+	if frontmatter != "" {
+		if err := yaml.Unmarshal([]byte(frontmatter), &tpl.Defaults); err != nil {
+			return nil, fmt.Errorf("invalid template frontmatter in %s: %w", name, err)
+		}
+	}
+	tpl.Body = strings.TrimLeft(body, "\n")
+
+	return tpl, nil
+}
+
+// resolveTemplateName returns the template to use for a type: the one named
+// on the command line, or the configured default
+func resolveTemplateName(config Config, noteType, name string) string {
+	if name != "" {
+		return name
+	}
+	switch noteType {
+	case "task":
+		return config.TOMLConfig.Templates.Task
+	case "project":
+		return config.TOMLConfig.Templates.Project
+	default:
+		return config.TOMLConfig.Templates.Note
+	}
+}
+
+// promptReader is shared so several prompts can read from stdin in turn
+var promptReader = bufio.NewReader(os.Stdin)
+
+// render executes the template body. {{ prompt "Label" "default" }} asks
+// for a value on stdin; answers are remembered so a label can be reused.
+func (t *NoteTemplate) render(data TemplateData) (string, error) {
+	answers := make(map[string]string)
+
+	funcs := template.FuncMap{
+		"prompt": func(label string, defaults ...string) string {
+			if answer, ok := answers[label]; ok {
+				return answer
+			}
+			def := strings.Join(defaults, " ")
+			if def != "" {
+				fmt.Printf("%s [%s]: ", label, def)
+			} else {
+				fmt.Printf("%s: ", label)
+			}
+			answer, _ := promptReader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if answer == "" {
+				answer = def
+			}
+			answers[label] = answer
+			return answer
+		},
+		"now": func(layout string) string {
+			return time.Now().Format(layout)
+		},
+		"join": strings.Join,
+	}
+
+	tmpl, err := template.New(t.Name).Funcs(funcs).Parse(t.Body)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", t.Name, err)
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", t.Name, err)
+	}
+
+	return result.String(), nil
+}
+
+// mergeTags appends template tags that aren't already present
+func mergeTags(tags []string, defaults []string) []string {
+	for _, t := range defaults {
+		if !containsTag(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// templateDate resolves a date default, which may be relative ("3d", "friday")
+func templateDate(current, def string) (string, error) {
+	if current != "" || def == "" {
+		return current, nil
+	}
+	return parseDate(def)
+}
+
+// applyTaskTemplate fills unset task fields from the template and renders its body
+func applyTaskTemplate(config Config, name, title string, meta *TaskMetadata, tags *[]string) (string, error) {
+	name = resolveTemplateName(config, "task", name)
+	if name == "" {
+		return "", nil
+	}
+
+	tpl, err := loadTemplate(name)
+	if err != nil {
+		return "", err
+	}
+	d := tpl.Defaults
+	if d.Status != "" && !isValidStatus(d.Status) {
+		return "", fmt.Errorf("invalid status in template %s: %s (must be open, done, paused, delegated, or dropped)", name, d.Status)
+	}
+	if d.Priority != "" && !isValidPriority(d.Priority) {
+		return "", fmt.Errorf("invalid priority in template %s: %s (must be p1, p2, or p3)", name, d.Priority)
+	}
+
+	if meta.Status == "" {
+		meta.Status = d.Status
+	}
+	if meta.Priority == "" {
+		meta.Priority = d.Priority
+	}
+	if meta.Estimate == 0 {
+		meta.Estimate = d.Estimate
+	}
+	if meta.Project == "" {
+		meta.Project = d.Project
+	}
+	if meta.Area == "" {
+		meta.Area = d.Area
+	}
+	if meta.Assignee == "" {
+		meta.Assignee = d.Assignee
+	}
+	if meta.DueDate, err = templateDate(meta.DueDate, d.DueDate); err != nil {
+		return "", err
+	}
+	if meta.StartDate, err = templateDate(meta.StartDate, d.StartDate); err != nil {
+		return "", err
+	}
+	*tags = mergeTags(*tags, d.Tags)
+
+	return tpl.render(TemplateData{
+		Type:     "task",
+		Title:    title,
+		Date:     time.Now().Format("2006-01-02"),
+		Tags:     *tags,
+		Priority: meta.Priority,
+		DueDate:  meta.DueDate,
+		Project:  meta.Project,
+		Area:     meta.Area,
+	})
+}
+
+// applyProjectTemplate fills unset project fields from the template and renders its body
+func applyProjectTemplate(config Config, name, title string, meta *ProjectMetadata, tags *[]string) (string, error) {
+	name = resolveTemplateName(config, "project", name)
+	if name == "" {
+		return "", nil
+	}
+
+	tpl, err := loadTemplate(name)
+	if err != nil {
+		return "", err
+	}
+	d := tpl.Defaults
+	if d.Status != "" && !isValidProjectStatus(d.Status) {
+		return "", fmt.Errorf("invalid status in template %s: %s (must be active, completed, paused, or cancelled)", name, d.Status)
+	}
+	if d.Priority != "" && !isValidPriority(d.Priority) {
+		return "", fmt.Errorf("invalid priority in template %s: %s (must be p1, p2, or p3)", name, d.Priority)
+	}
+
+	if meta.Status == "" {
+		meta.Status = d.Status
+	}
+	if meta.Priority == "" {
+		meta.Priority = d.Priority
+	}
+	if meta.Area == "" {
+		meta.Area = d.Area
+	}
+	if meta.DueDate, err = templateDate(meta.DueDate, d.DueDate); err != nil {
+		return "", err
+	}
+	if meta.StartDate, err = templateDate(meta.StartDate, d.StartDate); err != nil {
+		return "", err
+	}
+	*tags = mergeTags(*tags, d.Tags)
+
+	return tpl.render(TemplateData{
+		Type:     "project",
+		Title:    title,
+		Date:     time.Now().Format("2006-01-02"),
+		Tags:     *tags,
+		Priority: meta.Priority,
+		DueDate:  meta.DueDate,
+		Area:     meta.Area,
+	})
+}
+
+// applyNoteTemplate adds the template's tags and renders its body
+func applyNoteTemplate(config Config, name, title string, tags *[]string) (string, error) {
+	name = resolveTemplateName(config, "note", name)
+	if name == "" {
+		return "", nil
+	}
+
+	tpl, err := loadTemplate(name)
+	if err != nil {
+		return "", err
+	}
+	*tags = mergeTags(*tags, tpl.Defaults.Tags)
+
+	return tpl.render(TemplateData{
+		Type:  "note",
+		Title: title,
+		Date:  time.Now().Format("2006-01-02"),
+		Tags:  *tags,
+	})
+}
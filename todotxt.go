@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// todo.txt format: https://github.com/todotxt/todo.txt
+//
+//	x 2026-03-02 2026-03-01 Call Mom +Family @phone due:2026-03-05
+//	(A) 2026-03-01 Write report +Work-Stuff @office t:2026-03-03
+//
+// Fields with no todo.txt syntax use the common key:value extension:
+// status: for paused/delegated/dropped, pri: for the priority of completed
+// tasks, t: for the start (threshold) date and tags: for extra tags.
+
+var (
+	todoDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	todoPriorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoKeyValuePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^\s:/][^\s]*)$`)
+)
+
+func todoPriority(p string) string {
+	switch p {
+	case "p1":
+		return "A"
+	case "p2":
+		return "B"
+	case "p3":
+		return "C"
+	}
+	return ""
+}
+
+func priorityFromTodo(letter string) string {
+	switch letter {
+	case "A":
+		return "p1"
+	case "B":
+		return "p2"
+	case "C":
+		return "p3"
+	}
+	return ""
+}
+
+// todoProject turns a project name into a +project token, which can't
+// contain spaces
+func todoProject(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// todoContextEscaper percent-encodes the spaces an @context token can't
+// hold, and percent signs so the encoding can be undone exactly
+var (
+	todoContextEscaper   = strings.NewReplacer("%", "%25", " ", "%20")
+	todoContextUnescaper = strings.NewReplacer("%25", "%", "%20", " ")
+)
+
+// todoContext turns an area into an @context token: "home office" becomes
+// home%20office, while home_office stays as it is
+func todoContext(area string) string {
+	return todoContextEscaper.Replace(strings.Join(strings.Fields(area), " "))
+}
+
+func areaFromTodo(context string) string {
+	return todoContextUnescaper.Replace(context)
+}
+
+// formatTodoLine renders a task as one todo.txt line
+func formatTodoLine(task TaskInfo) string {
+	var parts []string
+
+	completed := task.Status == "done" || task.Status == "dropped"
+	if completed {
+		parts = append(parts, "x", task.completedOn())
+	} else if letter := todoPriority(task.Priority); letter != "" {
+		parts = append(parts, "("+letter+")")
+	}
+
+	if created, err := time.ParseInLocation(denoteIDFormat, task.Note.ID, time.Local); err == nil {
+		parts = append(parts, created.Format("2006-01-02"))
+	}
+
+	parts = append(parts, strings.Join(strings.Fields(task.Note.Title), " "))
+
+	if task.Project != "" {
+		parts = append(parts, "+"+todoProject(task.Project))
+	}
+	if task.Area != "" {
+		parts = append(parts, "@"+todoContext(task.Area))
+	}
+	if task.DueDate != "" {
+		parts = append(parts, "due:"+task.DueDate)
+	}
+	if task.StartDate != "" {
+		parts = append(parts, "t:"+task.StartDate)
+	}
+	if completed && task.Priority != "" {
+		parts = append(parts, "pri:"+todoPriority(task.Priority))
+	}
+	if task.Status != "open" && task.Status != "done" && task.Status != "" {
+		parts = append(parts, "status:"+task.Status)
+	}
+
+	var tags []string
+	for _, tag := range task.Note.Tags {
+		if tag != "task" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		parts = append(parts, "tags:"+strings.Join(tags, ","))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func exportTodoTxt(config Config, filters TaskFilters, output string) error {
+	tasks, err := findTasks(config, filters)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, task := range tasks {
+		buf.WriteString(formatTodoLine(task))
+		buf.WriteString("\n")
+	}
+
+	if output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("%s Exported %s to %s\n", success("✓"), count(len(tasks), pluralize(len(tasks), "task", "tasks")), output)
+
+	return nil
+}
+
+// todoItem is a parsed todo.txt line
+type todoItem struct {
+	Title string
+	Meta  TaskMetadata
+	Tags  []string
+}
+
+// parseTodoLine parses one todo.txt line. projects maps +project tokens
+// back to existing project names.
+func parseTodoLine(line string, projects map[string]string) (*todoItem, error) {
+	fields := strings.Fields(line)
+	item := &todoItem{Meta: TaskMetadata{Status: "open"}}
+
+	if len(fields) > 0 && fields[0] == "x" {
+		item.Meta.Status = "done"
+		fields = fields[1:]
+		// Completion date, then creation date
+		for i := 0; i < 2 && len(fields) > 0 && todoDatePattern.MatchString(fields[0]); i++ {
+			if _, err := time.Parse("2006-01-02", fields[0]); i == 0 && err == nil {
+				item.Meta.CompletedDate = fields[0]
+			}
+			fields = fields[1:]
+		}
+	} else {
+		if len(fields) > 0 {
+			if m := todoPriorityPattern.FindStringSubmatch(fields[0]); m != nil {
+				item.Meta.Priority = priorityFromTodo(m[1])
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 && todoDatePattern.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+	}
+
+	var words []string
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			if item.Meta.Project != "" {
+				words = append(words, field)
+				continue
+			}
+			name := field[1:]
+			if existing, ok := projects[strings.ToLower(name)]; ok {
+				name = existing
+			}
+			item.Meta.Project = name
+
+		case len(field) > 1 && field[0] == '@':
+			if item.Meta.Area == "" {
+				item.Meta.Area = areaFromTodo(field[1:])
+			} else if tag := sluggifyKeyword(field[1:]); tag != "" && !containsTag(item.Tags, tag) {
+				item.Tags = append(item.Tags, tag)
+			}
+
+		case todoKeyValuePattern.MatchString(field):
+			m := todoKeyValuePattern.FindStringSubmatch(field)
+			key, value := strings.ToLower(m[1]), m[2]
+			switch key {
+			case "due", "t":
+				if _, err := time.Parse("2006-01-02", value); err != nil {
+					return nil, fmt.Errorf("invalid %s date %q", key, value)
+				}
+				if key == "due" {
+					item.Meta.DueDate = value
+				} else {
+					item.Meta.StartDate = value
+				}
+			case "pri":
+				item.Meta.Priority = priorityFromTodo(strings.ToUpper(value))
+			case "status":
+				if !isValidStatus(value) {
+					return nil, fmt.Errorf("invalid status %q", value)
+				}
+				item.Meta.Status = value
+			case "tags":
+				for _, t := range strings.Split(value, ",") {
+					if tag := sluggifyKeyword(t); tag != "" && tag != "task" && !containsTag(item.Tags, tag) {
+						item.Tags = append(item.Tags, tag)
+					}
+				}
+			default:
+				// Unknown extensions stay part of the title
+				words = append(words, field)
+			}
+
+		default:
+			words = append(words, field)
+		}
+	}
+
+	item.Title = strings.Join(words, " ")
+	if item.Title == "" {
+		return nil, fmt.Errorf("no task description")
+	}
+
+	return item, nil
+}
+
+func importTodoTxt(config Config, path string, dryRun bool) error {
+	data, err := readImportFile(path)
+	if err != nil {
+		return err
+	}
+
+	projects := make(map[string]string)
+	if existing, err := findProjects(config, ProjectFilters{All: true}); err == nil {
+		for _, project := range existing {
+			projects[strings.ToLower(todoProject(project.Note.Title))] = project.Note.Title
+		}
+	}
+
+	if dryRun {
+		fmt.Println(dim("Dry run: nothing will be changed"))
+	}
+
+	imported := 0
+	var problems []string
+	gitStartBatch()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		item, err := parseTodoLine(line, projects)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v: %s", lineNum, err, line))
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("  %s %s\n", info("+"), item.Title)
+			imported++
+			continue
+		}
+
+		task, err := createTaskSilently(config, item.Title, item.Meta, item.Tags, "")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", lineNum, err))
+			continue
+		}
+		fmt.Printf("  %s #%d %s\n", info("+"), task.TaskID, item.Title)
+		imported++
+	}
+	gitFinishBatch(config, importCommitMessage(path, imported))
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fmt.Printf("%s Imported %s\n", success("✓"), count(imported, pluralize(imported, "task", "tasks")))
+
+	if len(problems) > 0 {
+		fmt.Printf("%s %d %s could not be parsed:\n", warning("!"), len(problems), pluralize(len(problems), "line", "lines"))
+		for _, problem := range problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,744 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+type tuiPane int
+
+const (
+	paneTasks tuiPane = iota
+	paneProjects
+	paneNotes
+)
+
+var tuiPaneNames = []string{"Tasks", "Projects", "Notes"}
+
+// Filter and sort values cycled by the toggle keys ("" means any)
+var (
+	tuiStatusCycle      = []string{"open", "", "paused", "delegated", "done", "dropped"}
+	tuiPriorityCycle    = []string{"", "p1", "p2", "p3"}
+	tuiTaskSortCycle    = []string{"priority", "due", "modified", "created", "start", "estimate"}
+	tuiProjectSortCycle = []string{"modified", "priority", "due", "created", "name", "area"}
+	tuiNoteSortCycle    = []string{"modified", "created", "title", "signature"}
+)
+
+const tuiHelp = "j/k move  tab pane  d done  +/- priority  D due  l log  e edit  f status  p priority  s sort  r reverse  q quit"
+
+type tui struct {
+	config         Config
+	out            *os.File
+	oldState       *term.State
+	pane           tuiPane
+	taskFilters    TaskFilters
+	projectFilters ProjectFilters
+	noteFilters    NoteFilters
+	tasks          []TaskInfo
+	projects       []ProjectInfo
+	notes          []NoteInfo
+	cursor         [3]int
+	offset         [3]int
+	message        string
+	prompt         string
+	input          string
+}
+
+// runTUI starts the full-screen interface. When stdin or stdout is not a
+// terminal it falls back to a plain task listing.
+func runTUI(config Config) error {
+	if !isTerminal(os.Stdout) || !isTerminal(os.Stdin) {
+		return listTasks(config, TaskFilters{SortBy: "priority"})
+	}
+
+	t := &tui{
+		config:         config,
+		out:            os.Stdout,
+		taskFilters:    TaskFilters{Status: "open", SortBy: "priority"},
+		projectFilters: ProjectFilters{Status: "active", SortBy: "modified"},
+		noteFilters:    NoteFilters{SortBy: "modified"},
+		message:        tuiHelp,
+	}
+
+	if err := t.enter(); err != nil {
+		return err
+	}
+	defer t.leave()
+
+	t.reload()
+
+	buf := make([]byte, 32)
+	for {
+		t.draw()
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		if t.handleKey(parseKey(buf[:n])) {
+			return nil
+		}
+	}
+}
+
+// enter switches the terminal to raw mode and the alternate screen
+func (t *tui) enter() error {
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	t.oldState = state
+	fmt.Fprint(t.out, "\033[?1049h\033[?25l")
+	return nil
+}
+
+// leave restores the terminal to the state it was in before enter
+func (t *tui) leave() {
+	fmt.Fprint(t.out, "\033[?25h\033[?1049l")
+	if t.oldState != nil {
+		term.Restore(int(os.Stdin.Fd()), t.oldState)
+		t.oldState = nil
+	}
+}
+
+// parseKey turns raw terminal input into a key name
+func parseKey(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	if len(b) == 1 {
+		switch b[0] {
+		case 3:
+			return "ctrl-c"
+		case 9:
+			return "tab"
+		case 10, 13:
+			return "enter"
+		case 27:
+			return "esc"
+		case 8, 127:
+			return "backspace"
+		}
+		return string(b)
+	}
+
+	if b[0] == 27 && len(b) >= 3 && (b[1] == '[' || b[1] == 'O') {
+		switch b[2] {
+		case 'A':
+			return "up"
+		case 'B':
+			return "down"
+		case 'C':
+			return "right"
+		case 'D':
+			return "left"
+		case 'H':
+			return "home"
+		case 'F':
+			return "end"
+		case 'Z':
+			return "shift-tab"
+		case '5':
+			return "pgup"
+		case '6':
+			return "pgdn"
+		}
+		return ""
+	}
+
+	return string(b)
+}
+
+// reload re-reads the current pane, keeping the selection on the same file
+func (t *tui) reload() {
+	selected := t.selectedPath()
+
+	var err error
+	switch t.pane {
+	case paneTasks:
+		t.tasks, err = findTasks(t.config, t.taskFilters)
+	case paneProjects:
+		t.projects, err = findProjects(t.config, t.projectFilters)
+	case paneNotes:
+		t.notes, err = findNotes(t.config, t.noteFilters)
+	}
+	if err != nil {
+		t.message = errorMsg(err.Error())
+	}
+
+	t.cursor[t.pane] = 0
+	for i := 0; i < t.count(); i++ {
+		if t.pathAt(i) == selected {
+			t.cursor[t.pane] = i
+			break
+		}
+	}
+	t.clamp()
+}
+
+func (t *tui) count() int {
+	switch t.pane {
+	case paneTasks:
+		return len(t.tasks)
+	case paneProjects:
+		return len(t.projects)
+	default:
+		return len(t.notes)
+	}
+}
+
+func (t *tui) pathAt(i int) string {
+	if i < 0 || i >= t.count() {
+		return ""
+	}
+	switch t.pane {
+	case paneTasks:
+		return t.tasks[i].Path
+	case paneProjects:
+		return t.projects[i].Path
+	default:
+		return t.notes[i].Path
+	}
+}
+
+func (t *tui) selectedPath() string {
+	return t.pathAt(t.cursor[t.pane])
+}
+
+func (t *tui) clamp() {
+	n := t.count()
+	if t.cursor[t.pane] >= n {
+		t.cursor[t.pane] = n - 1
+	}
+	if t.cursor[t.pane] < 0 {
+		t.cursor[t.pane] = 0
+	}
+}
+
+func (t *tui) size() (int, int) {
+	width, height, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// segment is a piece of a row with its color
+type segment struct {
+	text  string
+	style func(string) string
+}
+
+// fitSegments renders segments into exactly width columns, truncating with …
+func fitSegments(segments []segment, width int, plain bool) string {
+	var b strings.Builder
+	used := 0
+
+	for _, seg := range segments {
+		if used >= width {
+			break
+		}
+		text := seg.text
+		if n := utf8.RuneCountInString(text); used+n > width {
+			runes := []rune(text)
+			text = string(runes[:width-used-1]) + "…"
+		}
+		used += utf8.RuneCountInString(text)
+		if plain || seg.style == nil {
+			b.WriteString(text)
+		} else {
+			b.WriteString(seg.style(text))
+		}
+	}
+
+	if used < width {
+		b.WriteString(strings.Repeat(" ", width-used))
+	}
+	return b.String()
+}
+
+func (t *tui) rowSegments(i int) []segment {
+	switch t.pane {
+	case paneTasks:
+		task := t.tasks[i]
+		id := task.Index
+		if task.TaskID > 0 {
+			id = task.TaskID
+		}
+		segs := []segment{
+			{fmt.Sprintf("%4d ", id), bold},
+			{getStatusIcon(task.Status) + " ", func(string) string { return status(task.Status) + " " }},
+		}
+		if task.Priority != "" {
+			segs = append(segs, segment{"[" + strings.ToUpper(task.Priority) + "] ", func(s string) string {
+				return priority(task.Priority) + " "
+			}})
+		}
+		segs = append(segs, segment{task.Note.Title, nil})
+		if task.Project != "" {
+			segs = append(segs, segment{" @" + task.Project, blue})
+		}
+		if task.DueDate != "" {
+			overdueFlag := isOverdue(task.DueDate)
+			segs = append(segs, segment{formatDueDate(task.DueDate), func(s string) string { return due(s, overdueFlag) }})
+		}
+		return segs
+	case paneProjects:
+		p := t.projects[i]
+		id := p.Index
+		if p.ProjectID > 0 {
+			id = p.ProjectID
+		}
+		segs := []segment{
+			{fmt.Sprintf("%4d ", id), bold},
+			{getProjectStatusIcon(p.Status) + " ", func(string) string { return projectStatus(p.Status) + " " }},
+		}
+		if p.Area != "" {
+			segs = append(segs, segment{p.Area + " / ", cyan})
+		}
+		segs = append(segs, segment{p.Note.Title, nil})
+		if p.DueDate != "" {
+			overdueFlag := isOverdue(p.DueDate)
+			segs = append(segs, segment{formatProjectDueDate(p.DueDate), func(s string) string { return due(s, overdueFlag) }})
+		}
+		return segs
+	default:
+		n := t.notes[i]
+		segs := []segment{{fmt.Sprintf("%4d ", n.Index), bold}}
+		if n.Note.Signature != "" {
+			segs = append(segs, segment{"=" + n.Note.Signature + " ", gray})
+		}
+		segs = append(segs, segment{n.Note.Title, nil})
+		if len(n.Note.Tags) > 0 {
+			segs = append(segs, segment{" #" + strings.Join(n.Note.Tags, " #"), magenta})
+		}
+		return segs
+	}
+}
+
+// previewLines returns the header and body of the selected file
+func (t *tui) previewLines(width int) []string {
+	path := t.selectedPath()
+	if path == "" {
+		return nil
+	}
+
+	var lines []string
+	switch t.pane {
+	case paneTasks:
+		task := t.tasks[t.cursor[t.pane]]
+		lines = append(lines, task.Note.Title, "status: "+task.Status)
+		if task.Priority != "" {
+			lines = append(lines, "priority: "+task.Priority)
+		}
+		if task.DueDate != "" {
+			lines = append(lines, "due: "+getDueDateDisplay(task.DueDate))
+		}
+		if task.Project != "" {
+			lines = append(lines, "project: "+task.Project)
+		}
+		if task.Area != "" {
+			lines = append(lines, "area: "+task.Area)
+		}
+	case paneProjects:
+		p := t.projects[t.cursor[t.pane]]
+		lines = append(lines, p.Note.Title, "status: "+p.Status)
+		if p.DueDate != "" {
+			lines = append(lines, "due: "+getDueDateDisplay(p.DueDate))
+		}
+	default:
+		n := t.notes[t.cursor[t.pane]]
+		lines = append(lines, n.Note.Title)
+		if len(n.Note.Tags) > 0 {
+			lines = append(lines, "tags: "+strings.Join(n.Note.Tags, ", "))
+		}
+	}
+	lines = append(lines, "")
+
+	body, err := readNoteBody(path)
+	if err != nil {
+		return append(lines, err.Error())
+	}
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		lines = append(lines, wrapLine(strings.TrimRight(line, "\r"), width)...)
+	}
+
+	return lines
+}
+
+func wrapLine(line string, width int) []string {
+	if width <= 0 {
+		return nil
+	}
+	runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+	if len(runes) <= width {
+		return []string{string(runes)}
+	}
+	var lines []string
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
+
+func (t *tui) filterSummary() string {
+	orAny := func(s string) string {
+		if s == "" {
+			return "any"
+		}
+		return s
+	}
+	arrow := func(reverse bool) string {
+		if reverse {
+			return "↑"
+		}
+		return "↓"
+	}
+
+	switch t.pane {
+	case paneTasks:
+		summary := fmt.Sprintf("status:%s priority:%s sort:%s%s", orAny(t.taskFilters.Status),
+			orAny(t.taskFilters.Priority), t.taskFilters.SortBy, arrow(t.taskFilters.Reverse))
+		if t.taskFilters.Project != "" {
+			summary += " project:" + t.taskFilters.Project
+		}
+		return summary
+	case paneProjects:
+		return fmt.Sprintf("status:%s sort:%s%s", orAny(t.projectFilters.Status),
+			t.projectFilters.SortBy, arrow(t.projectFilters.Reverse))
+	default:
+		return fmt.Sprintf("sort:%s%s", t.noteFilters.SortBy, arrow(t.noteFilters.Reverse))
+	}
+}
+
+func (t *tui) draw() {
+	width, height := t.size()
+
+	listWidth := width
+	previewWidth := 0
+	if width >= 70 {
+		listWidth = width * 3 / 5
+		previewWidth = width - listWidth - 3
+	}
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+
+	// Keep the cursor on screen
+	cursor := t.cursor[t.pane]
+	if cursor < t.offset[t.pane] {
+		t.offset[t.pane] = cursor
+	}
+	if cursor >= t.offset[t.pane]+rows {
+		t.offset[t.pane] = cursor - rows + 1
+	}
+	offset := t.offset[t.pane]
+
+	var screen []string
+
+	// Header: pane tabs and the active filters
+	var header []segment
+	for i, name := range tuiPaneNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if tuiPane(i) == t.pane {
+			header = append(header, segment{label, func(s string) string { return color("\033[7m", s) }})
+		} else {
+			header = append(header, segment{label, dim})
+		}
+	}
+	header = append(header, segment{"  " + t.filterSummary(), gray})
+	screen = append(screen, fitSegments(header, width, false))
+	screen = append(screen, gray(strings.Repeat("─", width)))
+
+	var preview []string
+	if previewWidth > 0 {
+		preview = t.previewLines(previewWidth)
+	}
+
+	for r := 0; r < rows; r++ {
+		i := offset + r
+		line := strings.Repeat(" ", listWidth)
+		if i < t.count() {
+			if i == cursor {
+				line = color("\033[7m", fitSegments(t.rowSegments(i), listWidth, true))
+			} else {
+				line = fitSegments(t.rowSegments(i), listWidth, false)
+			}
+		} else if i == 0 {
+			line = fitSegments([]segment{{"  Nothing to show", dim}}, listWidth, false)
+		}
+
+		if previewWidth > 0 {
+			previewLine := ""
+			if r < len(preview) {
+				previewLine = preview[r]
+			}
+			style := func(s string) string { return s }
+			if r == 0 {
+				style = bold
+			}
+			line += gray(" │ ") + fitSegments([]segment{{previewLine, style}}, previewWidth, false)
+		}
+		screen = append(screen, line)
+	}
+
+	screen = append(screen, gray(strings.Repeat("─", width)))
+	if t.prompt != "" {
+		screen = append(screen, fitSegments([]segment{{t.prompt + ": ", bold}, {t.input + "█", nil}}, width, false))
+	} else {
+		screen = append(screen, fitSegments([]segment{{t.message, nil}}, width, false))
+	}
+
+	fmt.Fprint(t.out, "\033[H"+strings.Join(screen, "\033[K\r\n")+"\033[K")
+}
+
+// handleKey applies a key press and reports whether to quit
+func (t *tui) handleKey(key string) bool {
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "tab", "right":
+		t.switchPane((t.pane + 1) % 3)
+	case "shift-tab", "left":
+		t.switchPane((t.pane + 2) % 3)
+	case "1", "2", "3":
+		t.switchPane(tuiPane(key[0] - '1'))
+	case "j", "down":
+		t.cursor[t.pane]++
+	case "k", "up":
+		t.cursor[t.pane]--
+	case "g", "home":
+		t.cursor[t.pane] = 0
+	case "G", "end":
+		t.cursor[t.pane] = t.count() - 1
+	case "pgdn", " ":
+		_, height := t.size()
+		t.cursor[t.pane] += height - 4
+	case "pgup":
+		_, height := t.size()
+		t.cursor[t.pane] -= height - 4
+	case "?":
+		t.message = tuiHelp
+	case "R":
+		t.reload()
+		t.message = "Reloaded"
+	case "e":
+		t.editSelected()
+	case "r":
+		switch t.pane {
+		case paneTasks:
+			t.taskFilters.Reverse = !t.taskFilters.Reverse
+		case paneProjects:
+			t.projectFilters.Reverse = !t.projectFilters.Reverse
+		default:
+			t.noteFilters.Reverse = !t.noteFilters.Reverse
+		}
+		t.reload()
+	case "s":
+		switch t.pane {
+		case paneTasks:
+			t.taskFilters.SortBy = nextInCycle(tuiTaskSortCycle, t.taskFilters.SortBy)
+		case paneProjects:
+			t.projectFilters.SortBy = nextInCycle(tuiProjectSortCycle, t.projectFilters.SortBy)
+		default:
+			t.noteFilters.SortBy = nextInCycle(tuiNoteSortCycle, t.noteFilters.SortBy)
+		}
+		t.reload()
+	default:
+		switch t.pane {
+		case paneTasks:
+			t.handleTaskKey(key)
+		case paneProjects:
+			t.handleProjectKey(key)
+		default:
+			if key == "enter" {
+				t.editSelected()
+			}
+		}
+	}
+
+	t.clamp()
+	return false
+}
+
+func (t *tui) handleTaskKey(key string) {
+	if key == "f" {
+		t.taskFilters.Status = nextInCycle(tuiStatusCycle, t.taskFilters.Status)
+		t.reload()
+		return
+	}
+	if key == "p" {
+		t.taskFilters.Priority = nextInCycle(tuiPriorityCycle, t.taskFilters.Priority)
+		t.reload()
+		return
+	}
+	if key == "x" && t.taskFilters.Project != "" {
+		t.taskFilters.Project = ""
+		t.reload()
+		return
+	}
+
+	if len(t.tasks) == 0 {
+		return
+	}
+	task := t.tasks[t.cursor[t.pane]]
+	ref := task.Path
+	if task.TaskID > 0 {
+		ref = strconv.Itoa(task.TaskID)
+	}
+
+	switch key {
+	case "d":
+		newStatus := "done"
+		if task.Status == "done" {
+			newStatus = "open"
+		}
+		t.runAction(fmt.Sprintf("%s → %s", task.Note.Title, newStatus), func() error {
+			return updateTask(t.config, ref, TaskMetadata{Status: newStatus}, "")
+		})
+	case "+", "=":
+		newPriority := map[string]string{"": "p3", "p3": "p2", "p2": "p1", "p1": "p1"}[task.Priority]
+		t.runAction(fmt.Sprintf("%s → %s", task.Note.Title, newPriority), func() error {
+			return updateTask(t.config, ref, TaskMetadata{Priority: newPriority}, "")
+		})
+	case "-", "_":
+		if task.Priority == "" {
+			t.message = "No priority to lower"
+			return
+		}
+		newPriority := map[string]string{"p1": "p2", "p2": "p3", "p3": "p3"}[task.Priority]
+		t.runAction(fmt.Sprintf("%s → %s", task.Note.Title, newPriority), func() error {
+			return updateTask(t.config, ref, TaskMetadata{Priority: newPriority}, "")
+		})
+	case "D":
+		input, ok := t.readInput("Due date (today, fri, 3d, YYYY-MM-DD)")
+		if !ok || input == "" {
+			return
+		}
+		dueDate, err := parseDate(input)
+		if err != nil {
+			t.message = errorMsg(err.Error())
+			return
+		}
+		t.runAction(fmt.Sprintf("%s due %s", task.Note.Title, dueDate), func() error {
+			return updateTask(t.config, ref, TaskMetadata{DueDate: dueDate}, "")
+		})
+	case "l":
+		input, ok := t.readInput("Log entry")
+		if !ok || input == "" {
+			return
+		}
+		t.runAction("Logged to "+task.Note.Title, func() error {
+			return logToTask(t.config, ref, input)
+		})
+	case "enter":
+		t.editSelected()
+	}
+}
+
+func (t *tui) handleProjectKey(key string) {
+	switch key {
+	case "f":
+		if t.projectFilters.Status == "" {
+			t.projectFilters.Status = "active"
+		} else {
+			t.projectFilters.Status = ""
+		}
+		t.reload()
+	case "enter":
+		// Show the project's tasks
+		if len(t.projects) == 0 {
+			return
+		}
+		p := t.projects[t.cursor[t.pane]]
+		t.taskFilters.Project = p.Note.Title
+		t.taskFilters.Status = ""
+		t.switchPane(paneTasks)
+		t.message = "Tasks for " + p.Note.Title + " (x to clear)"
+	}
+}
+
+func (t *tui) switchPane(pane tuiPane) {
+	t.pane = pane
+	t.reload()
+}
+
+// runAction runs a mutating command with its output silenced, then reloads
+func (t *tui) runAction(description string, action func() error) {
+	if err := withoutStdout(action); err != nil {
+		t.message = errorMsg(err.Error())
+	} else {
+		t.message = success("✓ ") + description
+	}
+	t.reload()
+}
+
+// readInput prompts for a line of text on the status bar. It returns false
+// if the prompt was cancelled with Esc.
+func (t *tui) readInput(label string) (string, bool) {
+	t.prompt = label
+	t.input = ""
+	defer func() { t.prompt = "" }()
+
+	buf := make([]byte, 32)
+	for {
+		t.draw()
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return "", false
+		}
+
+		switch key := parseKey(buf[:n]); key {
+		case "enter":
+			return strings.TrimSpace(t.input), true
+		case "esc", "ctrl-c":
+			t.message = "Cancelled"
+			return "", false
+		case "backspace":
+			if runes := []rune(t.input); len(runes) > 0 {
+				t.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 && key[0] >= ' ' {
+				t.input += key
+			}
+		}
+	}
+}
+
+// editSelected opens the selected file in $EDITOR
+func (t *tui) editSelected() {
+	path := t.selectedPath()
+	if path == "" {
+		return
+	}
+
+	t.leave()
+	err := openInEditor(path)
+	if enterErr := t.enter(); enterErr != nil && err == nil {
+		err = enterErr
+	}
+
+	if err != nil {
+		t.message = errorMsg(err.Error())
+	} else {
+		t.message = "Edited " + path
+	}
+	t.reload()
+}
+
+func nextInCycle(cycle []string, current string) string {
+	for i, v := range cycle {
+		if v == current {
+			return cycle[(i+1)%len(cycle)]
+		}
+	}
+	return cycle[0]
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 )
@@ -54,4 +55,39 @@ func pluralize(count int, singular, plural string) string {
 		return singular
 	}
 	return plural
-}
\ No newline at end of file
+}
+
+// withoutStdout runs fn with os.Stdout pointed at the null device, for
+// reusing commands whose progress messages would get in the way
+func withoutStdout(fn func() error) error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer devNull.Close()
+
+	saved := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = saved }()
+
+	return fn()
+}
+
+// invalidError marks an error caused by the requested change itself (a bad
+// value, a tag outside the vocabulary, a change a hook stopped) rather than
+// by reading or writing files
+type invalidError struct {
+	err error
+}
+
+func (e *invalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidError) Unwrap() error {
+	return e.err
+}
+
+func invalidf(format string, args ...interface{}) error {
+	return &invalidError{fmt.Errorf(format, args...)}
+}
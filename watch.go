@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long watch waits for a burst of changes to end
+const watchDebounce = 200 * time.Millisecond
+
+// watchMode is set by the -watch flag of the list commands
+var watchMode bool
+
+func addWatchFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&watchMode, "watch", false, "Keep the list on screen and redraw it when files change")
+}
+
+// watch runs draw, then runs it again whenever a note or task file changes,
+// until interrupted
+func watch(config Config, draw func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watching: %w", err)
+	}
+	defer watcher.Close()
+
+	dirs := []string{config.NotesDir}
+	if config.TaskDir != config.NotesDir {
+		dirs = append(dirs, config.TaskDir)
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	fileCache = newParsedFiles()
+	defer func() { fileCache = nil }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	redraw := func() {
+		fmt.Print("\033[H\033[2J")
+		header := fmt.Sprintf("Watching %s, updated %s (Ctrl-C to quit)", strings.Join(dirs, ", "), time.Now().Format("15:04:05"))
+		fmt.Printf("%s\n\n", dim(header))
+		if err := draw(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+	redraw()
+
+	// Editors save in bursts (write a temp file, rename it, chmod), so
+	// redraw once things have settled
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !isWatchedFile(event.Name) {
+				continue
+			}
+			fileCache.forget(event.Name)
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "%s %v\n", warning("!"), err)
+		case <-debounce.C:
+			redraw()
+		}
+	}
+}
+
+// isWatchedFile reports whether a change to path can change a listing.
+// This leaves out notes-cli's own state files and editor lock files.
+func isWatchedFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".md") && !strings.HasPrefix(name, ".")
+}
+
+// parsedFiles keeps parsed files between redraws in watch mode. watch
+// forgets files as they change, so a redraw only reads those again.
+type parsedFiles struct {
+	tasks    map[string]*TaskInfo
+	projects map[string]*ProjectInfo
+	notes    map[string]*NoteInfo
+}
+
+// fileCache is nil outside watch mode
+var fileCache *parsedFiles
+
+func newParsedFiles() *parsedFiles {
+	return &parsedFiles{
+		tasks:    make(map[string]*TaskInfo),
+		projects: make(map[string]*ProjectInfo),
+		notes:    make(map[string]*NoteInfo),
+	}
+}
+
+func (c *parsedFiles) forget(path string) {
+	delete(c.tasks, path)
+	delete(c.projects, path)
+	delete(c.notes, path)
+}
+
+func cachedTaskFile(path string) (*TaskInfo, error) {
+	if fileCache == nil {
+		return parseTaskFile(path)
+	}
+	if task, ok := fileCache.tasks[path]; ok {
+		return task, nil
+	}
+	task, err := parseTaskFile(path)
+	if err == nil {
+		fileCache.tasks[path] = task
+	}
+	return task, err
+}
+
+func cachedProjectFile(path string) (*ProjectInfo, error) {
+	if fileCache == nil {
+		return parseProjectFile(path)
+	}
+	if project, ok := fileCache.projects[path]; ok {
+		return project, nil
+	}
+	project, err := parseProjectFile(path)
+	if err == nil {
+		fileCache.projects[path] = project
+	}
+	return project, err
+}
+
+func cachedNoteFile(path string) (*NoteInfo, error) {
+	if fileCache == nil {
+		return parseNoteInfo(path)
+	}
+	if note, ok := fileCache.notes[path]; ok {
+		return note, nil
+	}
+	note, err := parseNoteInfo(path)
+	if err == nil {
+		fileCache.notes[path] = note
+	}
+	return note, err
+}